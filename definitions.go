@@ -12,6 +12,7 @@ type Root struct {
 type BaseDefinition struct {
 	Description string `json:"description"`
 	Name        string `json:"name"`
+	Version     string `json:"version"`
 }
 
 func (bd BaseDefinition) Elem() interface{} {
@@ -91,6 +92,7 @@ func (td PackageDefinition) Elem() interface{} {
 func (td *PackageDefinition) Apply(item interface{}) error {
 	if def, ok := item.(Applicable); ok {
 		td.Definitions = append(td.Definitions, def)
+		return nil
 	}
 	return ErrNotApplicable
 }
@@ -123,9 +125,10 @@ func (td *TypeDefinition) Apply(item interface{}) error {
 // MethodDefinition defines the base definition for methods.
 type MethodDefinition struct {
 	BaseDefinition
-	Arguments []FieldDefinition
-	Returns   []ReturnDefinition
-	Data      Applicable
+	Arguments   []FieldDefinition
+	Returns     []ReturnDefinition
+	Annotations Annotations
+	Data        Applicable
 }
 
 func (td MethodDefinition) Elem() interface{} {
@@ -152,8 +155,15 @@ func (td *MethodDefinition) Apply(item interface{}) error {
 	case BaseDefinition:
 		td.BaseDefinition = ritem
 		return nil
+	case *AnnotationDefinition:
+		td.Annotations = append(td.Annotations, *ritem)
+		return nil
+	case AnnotationDefinition:
+		td.Annotations = append(td.Annotations, ritem)
+		return nil
 	case Applicable:
 		td.Data = ritem
+		return nil
 	}
 	return ErrNotApplicable
 }
@@ -231,6 +241,25 @@ type AnnotationDefinition struct {
 	Content string
 }
 
+// Annotations defines the list of annotations attached to a definition.
+type Annotations []AnnotationDefinition
+
+// Get returns the first annotation with the giving name.
+func (a Annotations) Get(name string) (AnnotationDefinition, bool) {
+	for _, annotation := range a {
+		if annotation.Name == name {
+			return annotation, true
+		}
+	}
+	return AnnotationDefinition{}, false
+}
+
+// Has returns true/false if an annotation with the giving name exists.
+func (a Annotations) Has(name string) bool {
+	var _, ok = a.Get(name)
+	return ok
+}
+
 func (td AnnotationDefinition) Elem() interface{} {
 	return td
 }
@@ -312,6 +341,20 @@ func (td VariableDefinition) Elem() interface{} {
 }
 
 func (td *VariableDefinition) Apply(item interface{}) error {
+	switch value := item.(type) {
+	case *BaseDefinition:
+		td.BaseDefinition = *value
+		return nil
+	case BaseDefinition:
+		td.BaseDefinition = value
+		return nil
+	case *AssignmentDefinition:
+		td.Assign = value
+		return nil
+	case Applicable:
+		td.Type = value
+		return nil
+	}
 	return ErrNotApplicable
 }
 
@@ -325,9 +368,6 @@ func (td ReturnDefinition) Elem() interface{} {
 }
 
 func (td *ReturnDefinition) Apply(item interface{}) error {
-	if value, ok := item.(Applicable); ok {
-		td.Type = value
-	}
 	switch value := item.(type) {
 	case *BaseDefinition:
 		td.BaseDefinition = *value
@@ -335,13 +375,17 @@ func (td *ReturnDefinition) Apply(item interface{}) error {
 	case BaseDefinition:
 		td.BaseDefinition = value
 		return nil
+	case Applicable:
+		td.Type = value
+		return nil
 	}
 	return ErrNotApplicable
 }
 
 type FieldDefinition struct {
 	BaseDefinition
-	Type Applicable
+	Type        Applicable
+	Annotations Annotations
 }
 
 func (td FieldDefinition) Elem() interface{} {
@@ -356,6 +400,12 @@ func (td *FieldDefinition) Apply(item interface{}) error {
 	case BaseDefinition:
 		td.BaseDefinition = value
 		return nil
+	case *AnnotationDefinition:
+		td.Annotations = append(td.Annotations, *value)
+		return nil
+	case AnnotationDefinition:
+		td.Annotations = append(td.Annotations, value)
+		return nil
 	case Applicable:
 		td.Type = value
 		return nil
@@ -391,8 +441,9 @@ func (td *DataTypeDefinition) Apply(item interface{}) error {
 
 type DataDefinition struct {
 	BaseDefinition
-	Fields []FieldDefinition
-	Methods []MethodDefinition
+	Fields      []FieldDefinition
+	Methods     []MethodDefinition
+	Annotations Annotations
 }
 
 func (td DataDefinition) Elem() interface{} {
@@ -416,9 +467,47 @@ func (td *DataDefinition) Apply(item interface{}) error {
 	case MethodDefinition:
 		td.Methods = append(td.Methods, value)
 		return nil
+	case *MethodDefinition:
+		td.Methods = append(td.Methods, *value)
+		return nil
 	case FieldDefinition:
 		td.Fields = append(td.Fields, value)
 		return nil
+	case *FieldDefinition:
+		td.Fields = append(td.Fields, *value)
+		return nil
+	case *AnnotationDefinition:
+		td.Annotations = append(td.Annotations, *value)
+		return nil
+	case AnnotationDefinition:
+		td.Annotations = append(td.Annotations, value)
+		return nil
+	}
+	return ErrNotApplicable
+}
+
+// BlockDefinition defines a sequence of statements which make up
+// the body of a method or a control flow definition.
+type BlockDefinition struct {
+	BaseDefinition
+	Statements []Applicable
+}
+
+func (td BlockDefinition) Elem() interface{} {
+	return td
+}
+
+func (td *BlockDefinition) Apply(item interface{}) error {
+	switch value := item.(type) {
+	case *BaseDefinition:
+		td.BaseDefinition = *value
+		return nil
+	case BaseDefinition:
+		td.BaseDefinition = value
+		return nil
+	case Applicable:
+		td.Statements = append(td.Statements, value)
+		return nil
 	}
 	return ErrNotApplicable
 }
@@ -429,7 +518,7 @@ type IfDefinition struct {
 	Body      Applicable
 }
 
-func (td IfDefinition) SetBody(body Applicable) {
+func (td *IfDefinition) SetBody(body Applicable) {
 	td.Body = body
 }
 
@@ -501,6 +590,7 @@ func (td *OperatorDefinition) Apply(item interface{}) error {
 	switch value := item.(type) {
 	case Operator:
 		td.Operator = value
+		return nil
 	case *BaseDefinition:
 		td.BaseDefinition = *value
 		return nil
@@ -517,7 +607,7 @@ type LoopDefinition struct {
 	Body      Applicable
 }
 
-func (td LoopDefinition) SetBody(body Applicable) {
+func (td *LoopDefinition) SetBody(body Applicable) {
 	td.Body = body
 }
 
@@ -554,6 +644,8 @@ func (td ConditionDefinition) Elem() interface{} {
 	return td
 }
 
+// Apply sets the operator of the condition, while other definitions
+// become its left and then right operand.
 func (td *ConditionDefinition) Apply(item interface{}) error {
 	switch value := item.(type) {
 	case *BaseDefinition:
@@ -562,6 +654,22 @@ func (td *ConditionDefinition) Apply(item interface{}) error {
 	case BaseDefinition:
 		td.BaseDefinition = value
 		return nil
+	case *OperatorDefinition:
+		td.Operator = *value
+		return nil
+	case OperatorDefinition:
+		td.Operator = value
+		return nil
+	case Applicable:
+		switch {
+		case td.Left == nil:
+			td.Left = value
+		case td.Right == nil:
+			td.Right = value
+		default:
+			return ErrNotApplicable
+		}
+		return nil
 	}
 	return ErrNotApplicable
 }
@@ -574,7 +682,7 @@ type ForDefinition struct {
 	Body   Applicable
 }
 
-func (td ForDefinition) SetBody(body Applicable) {
+func (td *ForDefinition) SetBody(body Applicable) {
 	td.Body = body
 }
 
@@ -582,6 +690,8 @@ func (td ForDefinition) Elem() interface{} {
 	return td
 }
 
+// Apply fills the init, condition and post clauses of the for
+// statement in order.
 func (td *ForDefinition) Apply(item interface{}) error {
 	switch value := item.(type) {
 	case *BaseDefinition:
@@ -590,6 +700,18 @@ func (td *ForDefinition) Apply(item interface{}) error {
 	case BaseDefinition:
 		td.BaseDefinition = value
 		return nil
+	case Applicable:
+		switch {
+		case td.Left == nil:
+			td.Left = value
+		case td.Middle == nil:
+			td.Middle = value
+		case td.End == nil:
+			td.End = value
+		default:
+			return ErrNotApplicable
+		}
+		return nil
 	}
 	return ErrNotApplicable
 }
@@ -600,7 +722,7 @@ type CaseDefinition struct {
 	Body      Applicable
 }
 
-func (td CaseDefinition) SetBody(body Applicable) {
+func (td *CaseDefinition) SetBody(body Applicable) {
 	td.Body = body
 }
 
@@ -616,6 +738,12 @@ func (td *CaseDefinition) Apply(item interface{}) error {
 	case BaseDefinition:
 		td.BaseDefinition = value
 		return nil
+	case ConditionDefinition:
+		td.Condition = value
+		return nil
+	case *ConditionDefinition:
+		td.Condition = *value
+		return nil
 	}
 	return ErrNotApplicable
 }
@@ -632,12 +760,21 @@ func (td SwitchDefinition) Elem() interface{} {
 
 func (td *SwitchDefinition) Apply(item interface{}) error {
 	switch value := item.(type) {
+	case *ConditionDefinition:
+		td.Condition = *value
+		return nil
 	case ConditionDefinition:
 		td.Condition = value
+		return nil
+	case *CaseDefinition:
+		td.Cases = append(td.Cases, *value)
+		return nil
 	case CaseDefinition:
 		td.Cases = append(td.Cases, value)
+		return nil
 	case []CaseDefinition:
 		td.Cases = append(td.Cases, value...)
+		return nil
 	case *BaseDefinition:
 		td.BaseDefinition = *value
 		return nil
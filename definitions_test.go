@@ -0,0 +1,63 @@
+package rewrite_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageDefinitionApply(t *testing.T) {
+	var pkg rewrite.PackageDefinition
+	require.NoError(t, pkg.Apply(&rewrite.DataDefinition{}))
+	require.Len(t, pkg.Definitions, 1)
+	require.Equal(t, rewrite.ErrNotApplicable, pkg.Apply("billing"))
+}
+
+func TestMethodDefinitionApply(t *testing.T) {
+	var method rewrite.MethodDefinition
+	require.NoError(t, method.Apply(&rewrite.FieldDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "id"}}))
+	require.NoError(t, method.Apply(&rewrite.ReturnDefinition{}))
+	require.NoError(t, method.Apply(&rewrite.ForDefinition{}))
+	require.Len(t, method.Arguments, 1)
+	require.Len(t, method.Returns, 1)
+	require.IsType(t, &rewrite.ForDefinition{}, method.Data)
+}
+
+func TestVariableDefinitionApply(t *testing.T) {
+	var variable rewrite.VariableDefinition
+	var assign = &rewrite.AssignmentDefinition{}
+	require.NoError(t, variable.Apply(rewrite.BaseDefinition{Name: "limit"}))
+	require.NoError(t, variable.Apply(assign))
+	require.NoError(t, variable.Apply(&rewrite.TypeDefinition{Type: rewrite.Integer}))
+	require.Equal(t, "limit", variable.Name)
+	require.Equal(t, assign, variable.Assign)
+	require.Equal(t, rewrite.TypeDefinition{Type: rewrite.Integer}, variable.Type.Elem())
+}
+
+func TestReturnDefinitionApply(t *testing.T) {
+	var ret rewrite.ReturnDefinition
+	require.NoError(t, ret.Apply(&rewrite.TypeDefinition{Type: rewrite.String}))
+	require.NoError(t, ret.Apply(rewrite.BaseDefinition{Name: "name"}))
+	require.Equal(t, "name", ret.Name)
+	require.Equal(t, rewrite.TypeDefinition{Type: rewrite.String}, ret.Type.Elem())
+	require.Equal(t, rewrite.ErrNotApplicable, ret.Apply("name"))
+}
+
+func TestDataDefinitionApply(t *testing.T) {
+	var data rewrite.DataDefinition
+	require.NoError(t, data.Apply(&rewrite.FieldDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "id"}}))
+	require.NoError(t, data.Apply(&rewrite.MethodDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "total"}}))
+	require.Equal(t, "id", data.Fields[0].Name)
+	require.Equal(t, "total", data.Methods[0].Name)
+}
+
+func TestSwitchDefinitionApply(t *testing.T) {
+	var sw rewrite.SwitchDefinition
+	require.NoError(t, sw.Apply(&rewrite.ConditionDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "kind"}}))
+	require.NoError(t, sw.Apply(&rewrite.CaseDefinition{}))
+	require.NoError(t, sw.Apply(rewrite.CaseDefinition{}))
+	require.Equal(t, "kind", sw.Condition.Name)
+	require.Len(t, sw.Cases, 2)
+	require.Equal(t, rewrite.ErrNotApplicable, sw.Apply("kind"))
+}
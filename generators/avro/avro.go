@@ -0,0 +1,174 @@
+// Package avro renders the data definitions of a rewrite.PackageDefinition
+// into Apache Avro record schemas.
+package avro
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Record defines an Avro record schema.
+type Record struct {
+	Type      string  `json:"type"`
+	Name      string  `json:"name"`
+	Namespace string  `json:"namespace,omitempty"`
+	Doc       string  `json:"doc,omitempty"`
+	Fields    []Field `json:"fields"`
+}
+
+// Field defines a field of a record, where Type is the name of a primitive
+// or named type, a Logical type or a nested Record.
+type Field struct {
+	Name string      `json:"name"`
+	Type interface{} `json:"type"`
+	Doc  string      `json:"doc,omitempty"`
+}
+
+// Logical defines a primitive type annotated with a logical type.
+type Logical struct {
+	Type        string `json:"type"`
+	LogicalType string `json:"logicalType"`
+}
+
+func init() {
+	typemap.Defaults("avro", typemap.Table{
+		{Type: rewrite.String}:                         {Name: "string"},
+		{Type: rewrite.Rune}:                           {Name: "string"},
+		{Type: rewrite.Integer}:                        {Name: "long"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "int"},
+		{Type: rewrite.Decimal}:                        {Name: "double"},
+		{Type: rewrite.Decimal, Memory: rewrite.Bit32}: {Name: "float"},
+		{Type: rewrite.Time}:                           {Name: "long", Format: "timestamp-millis"},
+	})
+	generators.Register(generators.Multiple("avro", []string{".avsc"}, Render))
+}
+
+// Render returns an Avro schema for each DataDefinition with fields of the
+// package, keyed by the definition name with an ".avsc" suffix. The package
+// name is used as the namespace of records.
+//
+// Records referenced by a schema are declared inline where first used and
+// referenced by their full name afterwards, as Avro requires.
+func Render(pkg rewrite.PackageDefinition) (map[string][]byte, error) {
+	var data = map[string]rewrite.DataDefinition{}
+	for _, definition := range pkg.Definitions {
+		if definition == nil {
+			continue
+		}
+		if def, ok := definition.Elem().(rewrite.DataDefinition); ok {
+			data[def.Name] = def
+		}
+	}
+
+	var schemas = map[string][]byte{}
+	for _, definition := range pkg.Definitions {
+		if definition == nil {
+			continue
+		}
+
+		var def, ok = definition.Elem().(rewrite.DataDefinition)
+		if !ok || len(def.Fields) == 0 {
+			continue
+		}
+
+		var b = builder{namespace: pkg.Name, data: data, declared: map[string]bool{}, types: typemap.For("avro")}
+		var record, err = b.record(def)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded, err := json.MarshalIndent(record, "", "\t")
+		if err != nil {
+			return nil, err
+		}
+		schemas[def.Name+".avsc"] = append(encoded, '\n')
+	}
+	return schemas, nil
+}
+
+// builder converts definitions into the schema of a single document.
+type builder struct {
+	namespace string
+	data      map[string]rewrite.DataDefinition
+	types     typemap.Table
+
+	// declared holds the records already declared within the document.
+	declared map[string]bool
+}
+
+func (b *builder) record(def rewrite.DataDefinition) (*Record, error) {
+	b.declared[def.Name] = true
+
+	var record = &Record{
+		Type:      "record",
+		Name:      def.Name,
+		Namespace: b.namespace,
+		Doc:       def.Description,
+		Fields:    make([]Field, 0, len(def.Fields)),
+	}
+
+	for _, field := range def.Fields {
+		var schema, err = b.schema(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("avro: field %q of %q: %w", field.Name, def.Name, err)
+		}
+		record.Fields = append(record.Fields, Field{Name: field.Name, Type: schema, Doc: field.Description})
+	}
+	return record, nil
+}
+
+// schema returns the Avro schema of a field type.
+func (b *builder) schema(definition rewrite.Applicable) (interface{}, error) {
+	if definition == nil {
+		return nil, fmt.Errorf("missing type definition")
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return b.baseType(def)
+	case rewrite.DataTypeDefinition:
+		if def.Type != nil {
+			return b.schema(def.Type)
+		}
+		return b.named(def.Name)
+	case rewrite.DataDefinition:
+		return b.named(def.Name)
+	case rewrite.FieldDefinition:
+		return b.schema(def.Type)
+	}
+	return nil, fmt.Errorf("%T has no Avro equivalent", definition.Elem())
+}
+
+// named returns the full name of a record already declared in the document,
+// or declares it inline if it is a DataDefinition of the package.
+func (b *builder) named(name string) (interface{}, error) {
+	var fullName = name
+	if b.namespace != "" {
+		fullName = b.namespace + "." + name
+	}
+
+	if b.declared[name] {
+		return fullName, nil
+	}
+	if def, ok := b.data[name]; ok {
+		return b.record(def)
+	}
+	return fullName, nil
+}
+
+// baseType returns the Avro type of a base type, a Logical type if its
+// mapping has a format.
+func (b *builder) baseType(def rewrite.TypeDefinition) (interface{}, error) {
+	var typ, ok = b.types.Lookup(def)
+	if !ok {
+		return nil, fmt.Errorf("base type %q has no Avro equivalent", def.Type)
+	}
+	if typ.Format != "" {
+		return Logical{Type: typ.Name, LogicalType: typ.Format}, nil
+	}
+	return typ.Name, nil
+}
@@ -0,0 +1,75 @@
+package avro_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/avro"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "events"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Point"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "x"}, Type: &rewrite.TypeDefinition{Type: rewrite.Decimal, Memory: rewrite.Bit32}},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "y"}, Type: &rewrite.TypeDefinition{Type: rewrite.Decimal}},
+				},
+			},
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Moved", Description: "A user moved."},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "user"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "count"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32}},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "at"}, Type: &rewrite.TypeDefinition{Type: rewrite.Time}},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "from"}, Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "Point"}}},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "to"}, Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "Point"}}},
+				},
+			},
+		},
+	}
+
+	var schemas, err = avro.Render(pkg)
+	require.NoError(t, err)
+	require.Len(t, schemas, 2)
+	require.JSONEq(t, `{
+		"type": "record",
+		"name": "Moved",
+		"namespace": "events",
+		"doc": "A user moved.",
+		"fields": [
+			{"name": "user", "type": "long"},
+			{"name": "count", "type": "int"},
+			{"name": "at", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+			{"name": "from", "type": {
+				"type": "record",
+				"name": "Point",
+				"namespace": "events",
+				"fields": [
+					{"name": "x", "type": "float"},
+					{"name": "y", "type": "double"}
+				]
+			}},
+			{"name": "to", "type": "events.Point"}
+		]
+	}`, string(schemas["Moved.avsc"]))
+}
+
+func TestRenderStreamField(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Feed"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "items"}, Type: &rewrite.StreamDefinition{Type: &rewrite.TypeDefinition{Type: rewrite.String}}},
+				},
+			},
+		},
+	}
+
+	var _, err = avro.Render(pkg)
+	require.Error(t, err)
+}
@@ -0,0 +1,252 @@
+// Package c renders a rewrite.PackageDefinition into a C header of structs
+// and function prototypes for FFI boundaries.
+package c
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Options configures how definitions are rendered.
+type Options struct {
+	// Guard sets the include guard macro, defaults to the upper case
+	// package definition name with a "_H" suffix.
+	Guard string
+}
+
+var nonIdentifier = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+func init() {
+	typemap.Defaults("c", typemap.Table{
+		{Type: rewrite.String}:                         {Name: "const char *"},
+		{Type: rewrite.Rune}:                           {Name: "int32_t", Import: "stdint.h"},
+		{Type: rewrite.Integer}:                        {Name: "int64_t", Import: "stdint.h"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "int32_t", Import: "stdint.h"},
+		{Type: rewrite.Decimal}:                        {Name: "double"},
+		{Type: rewrite.Decimal, Memory: rewrite.Bit32}: {Name: "float"},
+		{Type: rewrite.Time}:                           {Name: "int64_t", Import: "stdint.h"},
+	})
+	generators.Register(generators.Single("c", ".h", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg, Options{})
+	}))
+}
+
+// Render returns the C header for the giving package definition.
+//
+// DataDefinition with fields are rendered as structs, whose fields use the
+// fixed width type of their TypeDefinition.Memory, so int32_t or int64_t for
+// integers and float or double for decimals. Methods of a DataDefinition are
+// rendered as prototypes prefixed with its name, taking a pointer to the
+// struct as first argument when it has fields. Time is rendered as int64_t
+// milliseconds since the unix epoch.
+func Render(pkg rewrite.PackageDefinition, options Options) ([]byte, error) {
+	var r = renderer{
+		Renderer: generators.Renderer{
+			DocFormat: "/* %s */",
+			DocBlock:  [3]string{"/*", " * ", " */"},
+			DocEscape: generators.EscapeCommentEnd,
+		},
+		includes: map[string]bool{},
+		types:    typemap.For("c"),
+	}
+	for _, definition := range pkg.Definitions {
+		r.render(definition)
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	var guard = options.Guard
+	if guard == "" {
+		var name = pkg.Name
+		if name == "" {
+			name = "package"
+		}
+		guard = strings.ToUpper(nonIdentifier.ReplaceAllString(name, "_")) + "_H"
+	}
+
+	var out bytes.Buffer
+	if pkg.Description != "" {
+		fmt.Fprintf(&out, "%s\n", generators.Comment("/* %s */", generators.EscapeCommentEnd(pkg.Description)))
+	}
+	if pkg.Version != "" {
+		fmt.Fprintf(&out, "/* Version: %s */\n", pkg.Version)
+	}
+	fmt.Fprintf(&out, "#ifndef %s\n#define %s\n", guard, guard)
+	var includes = make([]string, 0, len(r.includes))
+	for header := range r.includes {
+		includes = append(includes, header)
+	}
+	sort.Strings(includes)
+	if len(includes) != 0 {
+		out.WriteString("\n")
+	}
+	for _, header := range includes {
+		fmt.Fprintf(&out, "#include <%s>\n", header)
+	}
+	out.WriteString("\n#ifdef __cplusplus\nextern \"C\" {\n#endif\n")
+	out.Write(r.Out.Bytes())
+	out.WriteString("\n#ifdef __cplusplus\n}\n#endif\n")
+	fmt.Fprintf(&out, "\n#endif /* %s */\n", guard)
+	return out.Bytes(), nil
+}
+
+type renderer struct {
+	generators.Renderer
+	types    typemap.Table
+	includes map[string]bool
+}
+
+func (r *renderer) render(definition rewrite.Applicable) {
+	if definition == nil {
+		return
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.CommentDefinition:
+		r.Printf("\n")
+		for _, line := range def.Contents {
+			r.Printf("/* %s */\n", line)
+		}
+	case rewrite.DataDefinition:
+		if len(def.Fields) != 0 {
+			r.renderStruct(def)
+		}
+		r.renderMethods(def)
+	case rewrite.DataTypeDefinition:
+		r.Doc("", def.Description)
+		r.Printf("typedef %s;\n", r.declaration(def.Type, def.Name))
+	case rewrite.TypeDefinition:
+		r.Doc("", def.Description)
+		r.Printf("typedef %s;\n", r.declaration(definition, def.Name))
+	case rewrite.MethodDefinition:
+		r.Doc("", def.Description)
+		r.Printf("%s;\n", r.prototype(def.Name, "", def))
+	case rewrite.VariableDefinition:
+		r.renderVariable(def)
+	}
+}
+
+func (r *renderer) renderStruct(def rewrite.DataDefinition) {
+	r.Doc("", def.Description)
+	r.Printf("typedef struct %s {\n", def.Name)
+	for _, field := range def.Fields {
+		r.Doc("    ", field.Description)
+		r.Printf("    %s;\n", r.declaration(field.Type, field.Name))
+	}
+	r.Printf("} %s;\n", def.Name)
+}
+
+func (r *renderer) renderMethods(def rewrite.DataDefinition) {
+	var self string
+	if len(def.Fields) != 0 {
+		self = def.Name + " *self"
+	}
+
+	if len(def.Fields) == 0 && len(def.Methods) != 0 && def.Description != "" {
+		r.Doc("", def.Description)
+	}
+	for _, method := range def.Methods {
+		r.Doc("", method.Description)
+		r.Printf("%s;\n", r.prototype(def.Name+"_"+method.Name, self, method))
+	}
+}
+
+func (r *renderer) renderVariable(def rewrite.VariableDefinition) {
+	if def.Type == nil {
+		r.SetErr(fmt.Errorf("c: variable %q requires a type", def.Name))
+		return
+	}
+
+	r.Doc("", def.Description)
+	if def.Constant && def.Assign != nil && def.Assign.Value != nil {
+		r.Printf("static const %s = %s;\n", r.declaration(def.Type, def.Name), generators.Value(def.Assign.Value, "0"))
+		return
+	}
+	r.Printf("extern %s;\n", r.declaration(def.Type, def.Name))
+}
+
+// prototype returns the function prototype of a method, self is prepended
+// to its parameters if not empty.
+func (r *renderer) prototype(name string, self string, def rewrite.MethodDefinition) string {
+	var params []string
+	if self != "" {
+		params = append(params, self)
+	}
+	for _, arg := range def.Arguments {
+		params = append(params, r.declaration(arg.Type, arg.Name))
+	}
+	if len(params) == 0 {
+		params = append(params, "void")
+	}
+
+	var returns = "void"
+	switch len(def.Returns) {
+	case 0:
+	case 1:
+		returns = r.typeName(def.Returns[0].Type)
+	default:
+		r.SetErr(fmt.Errorf("c: function %q returns more than one value", name))
+	}
+
+	var separator = " "
+	if strings.HasSuffix(returns, "*") {
+		separator = ""
+	}
+	return fmt.Sprintf("%s%s%s(%s)", returns, separator, name, strings.Join(params, ", "))
+}
+
+// declaration returns the declaration of a name with the giving type.
+func (r *renderer) declaration(definition rewrite.Applicable, name string) string {
+	var typeName = r.typeName(definition)
+	if strings.HasSuffix(typeName, "*") {
+		return typeName + name
+	}
+	return typeName + " " + name
+}
+
+// typeName returns the C type for the giving type definition.
+func (r *renderer) typeName(definition rewrite.Applicable) string {
+	if definition == nil {
+		return "void *"
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return r.baseType(def)
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return r.typeName(def.Type)
+		}
+		return def.Name
+	case rewrite.DataDefinition:
+		return def.Name
+	case rewrite.FieldDefinition:
+		return r.typeName(def.Type)
+	case rewrite.ReturnDefinition:
+		return r.typeName(def.Type)
+	}
+
+	r.SetErr(fmt.Errorf("c: %T has no C equivalent", definition.Elem()))
+	return ""
+}
+
+func (r *renderer) baseType(def rewrite.TypeDefinition) string {
+	var typ, ok = r.types.Lookup(def)
+	if !ok {
+		r.SetErr(fmt.Errorf("c: base type %q has no C equivalent", def.Type))
+		return ""
+	}
+	if typ.Import != "" {
+		r.includes[typ.Import] = true
+	}
+	return typ.Name
+}
@@ -0,0 +1,90 @@
+package c_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/c"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var point = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "Point", Description: "A sample position."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "x"}, Type: &rewrite.TypeDefinition{Type: rewrite.Decimal, Memory: rewrite.Bit32}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "y"}, Type: &rewrite.TypeDefinition{Type: rewrite.Decimal}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "count"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "at"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "label"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+		},
+		Methods: []rewrite.MethodDefinition{
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "scale"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "factor"}, Type: &rewrite.TypeDefinition{Type: rewrite.Decimal}},
+				},
+			},
+		},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "geometry"},
+		Definitions: []rewrite.Applicable{
+			point,
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "point_name", Description: "Returns the label of a point."},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "p"}, Type: point},
+				},
+				Returns: []rewrite.ReturnDefinition{{Type: &rewrite.TypeDefinition{Type: rewrite.String}}},
+			},
+		},
+	}
+
+	var code, err = c.Render(pkg, c.Options{})
+	require.NoError(t, err)
+	require.Equal(t, `#ifndef GEOMETRY_H
+#define GEOMETRY_H
+
+#include <stdint.h>
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+/* A sample position. */
+typedef struct Point {
+    float x;
+    double y;
+    int32_t count;
+    int64_t at;
+    const char *label;
+} Point;
+
+void Point_scale(Point *self, double factor);
+
+/* Returns the label of a point. */
+const char *point_name(Point p);
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif /* GEOMETRY_H */
+`, string(code))
+}
+
+func TestRenderFuture(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "load"},
+				Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: &rewrite.TypeDefinition{Type: rewrite.String}}}},
+			},
+		},
+	}
+
+	var _, err = c.Render(pkg, c.Options{})
+	require.Error(t, err)
+}
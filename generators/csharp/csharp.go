@@ -0,0 +1,367 @@
+// Package csharp renders a rewrite.PackageDefinition into a C# namespace of
+// records, classes and interfaces for .NET consumers.
+package csharp
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/naming"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Options configures how definitions are rendered.
+type Options struct {
+	// Namespace sets the C# namespace, defaults to the package definition name.
+	Namespace string
+
+	// Classes renders DataDefinition as classes with properties instead
+	// of positional records.
+	Classes bool
+}
+
+// xmlText escapes descriptions for XML doc comments.
+var xmlText = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func init() {
+	typemap.Defaults("csharp", typemap.Table{
+		{Type: rewrite.String}:                         {Name: "string"},
+		{Type: rewrite.Rune}:                           {Name: "char"},
+		{Type: rewrite.Integer}:                        {Name: "long"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "int"},
+		{Type: rewrite.Decimal}:                        {Name: "double"},
+		{Type: rewrite.Decimal, Memory: rewrite.Bit32}: {Name: "float"},
+		{Type: rewrite.Time}:                           {Name: "DateTimeOffset", Import: "System"},
+	})
+	naming.Defaults("csharp", naming.Convention{
+		Cases: map[naming.Construct]naming.Case{
+			naming.Type:     naming.Pascal,
+			naming.Field:    naming.Pascal,
+			naming.Method:   naming.Pascal,
+			naming.Constant: naming.Pascal,
+		},
+		Keywords: naming.Set(
+			"abstract", "as", "base", "bool", "break", "byte", "case", "catch",
+			"char", "checked", "class", "const", "continue", "decimal",
+			"default", "delegate", "do", "double", "else", "enum", "event",
+			"explicit", "extern", "false", "finally", "fixed", "float", "for",
+			"foreach", "goto", "if", "implicit", "in", "int", "interface",
+			"internal", "is", "lock", "long", "namespace", "new", "null",
+			"object", "operator", "out", "override", "params", "private",
+			"protected", "public", "readonly", "ref", "return", "sbyte",
+			"sealed", "short", "sizeof", "stackalloc", "static", "string",
+			"struct", "switch", "this", "throw", "true", "try", "typeof", "uint",
+			"ulong", "unchecked", "unsafe", "ushort", "using", "virtual", "void",
+			"volatile", "while",
+		),
+		Escape: func(name string) string {
+			return "@" + name
+		},
+	})
+	generators.Register(generators.Single("csharp", ".cs", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg, Options{})
+	}))
+}
+
+// Render returns the C# source for the giving package definition.
+//
+// DataDefinition with fields are rendered as records or classes, while those
+// with only methods are rendered as interfaces. Methods and variables declared
+// on the package are collected into a static class named after the package.
+// FutureDefinition are rendered as Task, StreamDefinition as IAsyncEnumerable
+// and ChannelDefinition as a Channel, ChannelReader or ChannelWriter following
+// its Direction. Statements found directly within the package are ignored.
+func Render(pkg rewrite.PackageDefinition, options Options) ([]byte, error) {
+	var r = renderer{
+		Renderer: generators.Renderer{
+			DocFormat: "/// <summary>%s</summary>",
+			DocBlock:  [3]string{"/// <summary>", "/// ", "/// </summary>"},
+			DocEscape: xmlText.Replace,
+		},
+		imports: map[string]bool{},
+		types:   typemap.For("csharp"),
+		names:   naming.For("csharp"),
+	}
+
+	var methods []rewrite.MethodDefinition
+	var variables []rewrite.VariableDefinition
+	for _, definition := range pkg.Definitions {
+		if definition == nil {
+			continue
+		}
+
+		switch def := definition.Elem().(type) {
+		case rewrite.CommentDefinition:
+			r.Printf("\n")
+			for _, line := range def.Contents {
+				r.Printf("// %s\n", line)
+			}
+		case rewrite.DataDefinition:
+			switch {
+			case len(def.Fields) == 0:
+				r.renderInterface(def)
+			case options.Classes:
+				r.renderClass(def)
+			default:
+				r.renderRecord(def)
+			}
+		case rewrite.MethodDefinition:
+			methods = append(methods, def)
+		case rewrite.VariableDefinition:
+			variables = append(variables, def)
+		}
+	}
+
+	if len(methods) != 0 || len(variables) != 0 {
+		var title = "Package"
+		if pkg.Name != "" {
+			title = strings.ToUpper(pkg.Name[:1]) + pkg.Name[1:]
+		}
+		r.renderStatic(title, methods, variables)
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	var out bytes.Buffer
+	if pkg.Description != "" {
+		fmt.Fprintf(&out, "%s\n", generators.Comment("// %s", pkg.Description))
+	}
+	if pkg.Version != "" {
+		fmt.Fprintf(&out, "// Version: %s\n", pkg.Version)
+	}
+
+	var imports = make([]string, 0, len(r.imports))
+	for namespace := range r.imports {
+		imports = append(imports, namespace)
+	}
+	sort.Strings(imports)
+	for _, namespace := range imports {
+		fmt.Fprintf(&out, "using %s;\n", namespace)
+	}
+
+	var namespace = options.Namespace
+	if namespace == "" {
+		namespace = pkg.Name
+	}
+	if namespace != "" {
+		if out.Len() != 0 {
+			out.WriteString("\n")
+		}
+		fmt.Fprintf(&out, "namespace %s;\n", namespace)
+	}
+
+	out.Write(r.Out.Bytes())
+	return out.Bytes(), nil
+}
+
+type renderer struct {
+	generators.Renderer
+	types   typemap.Table
+	names   naming.Convention
+	imports map[string]bool
+}
+
+// use records the using directive of a namespace, returning the name.
+func (r *renderer) use(namespace string, name string) string {
+	r.imports[namespace] = true
+	return name
+}
+
+func (r *renderer) renderRecord(def rewrite.DataDefinition) {
+	var parameters = make([]string, 0, len(def.Fields))
+	for _, field := range def.Fields {
+		var name = r.names.Name(naming.Field, field.Name)
+		var parameter = fmt.Sprintf("%s %s", r.typeName(field.Type), name)
+		if name != field.Name {
+			parameter = fmt.Sprintf("[property: %s(%q)] %s", r.use("System.Text.Json.Serialization", "JsonPropertyName"), field.Name, parameter)
+		}
+		parameters = append(parameters, parameter)
+	}
+
+	r.Doc("", def.Description)
+	r.Printf("public record %s(%s)", r.names.Name(naming.Type, def.Name), strings.Join(parameters, ", "))
+	if len(def.Methods) == 0 {
+		r.Printf(";\n")
+		return
+	}
+
+	r.Printf("\n{\n")
+	r.renderMethods(def.Methods, false)
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderClass(def rewrite.DataDefinition) {
+	r.Doc("", def.Description)
+	r.Printf("public class %s\n{\n", r.names.Name(naming.Type, def.Name))
+	for _, field := range def.Fields {
+		var name = r.names.Name(naming.Field, field.Name)
+		r.Doc("    ", field.Description)
+		if name != field.Name {
+			r.Printf("    [%s(%q)]\n", r.use("System.Text.Json.Serialization", "JsonPropertyName"), field.Name)
+		}
+		r.Printf("    public %s %s { get; set; }\n", r.typeName(field.Type), name)
+	}
+	if len(def.Methods) != 0 {
+		r.Printf("\n")
+		r.renderMethods(def.Methods, false)
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderInterface(def rewrite.DataDefinition) {
+	r.Doc("", def.Description)
+	r.Printf("public interface %s\n{\n", r.names.Name(naming.Type, def.Name))
+	for index, method := range def.Methods {
+		if index != 0 {
+			r.Printf("\n")
+		}
+		r.Doc("    ", method.Description)
+		r.Printf("    %s;\n", r.signature(method))
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderStatic(name string, methods []rewrite.MethodDefinition, variables []rewrite.VariableDefinition) {
+	r.Printf("\npublic static class %s\n{\n", name)
+	for _, variable := range variables {
+		if variable.Type == nil {
+			r.SetErr(fmt.Errorf("csharp: variable %q requires a type", variable.Name))
+			continue
+		}
+
+		var modifier = "static"
+		if variable.Constant {
+			modifier = "static readonly"
+			if _, ok := variable.Type.Elem().(rewrite.TypeDefinition); ok {
+				modifier = "const"
+			}
+		}
+
+		var name = variable.Name
+		if variable.Constant {
+			name = r.names.Name(naming.Constant, variable.Name)
+		}
+
+		r.Doc("    ", variable.Description)
+		r.Printf("    public %s %s %s", modifier, r.typeName(variable.Type), name)
+		if variable.Assign != nil && variable.Assign.Value != nil {
+			r.Printf(" = %s", generators.Value(variable.Assign.Value, "null"))
+		}
+		r.Printf(";\n")
+	}
+	if len(methods) != 0 && len(variables) != 0 {
+		r.Printf("\n")
+	}
+	r.renderMethods(methods, true)
+	r.Printf("}\n")
+}
+
+// renderMethods renders methods with bodies which are yet to be implemented.
+func (r *renderer) renderMethods(methods []rewrite.MethodDefinition, static bool) {
+	var modifier = "public"
+	if static {
+		modifier = "public static"
+	}
+
+	for index, method := range methods {
+		if index != 0 {
+			r.Printf("\n")
+		}
+		r.Doc("    ", method.Description)
+		r.Printf("    %s %s => throw new %s();\n", modifier, r.signature(method), r.use("System", "NotImplementedException"))
+	}
+}
+
+// signature returns the return type, name and parameters of a method.
+func (r *renderer) signature(def rewrite.MethodDefinition) string {
+	var args = make([]string, 0, len(def.Arguments))
+	for _, arg := range def.Arguments {
+		args = append(args, fmt.Sprintf("%s %s", r.typeName(arg.Type), arg.Name))
+	}
+	return fmt.Sprintf("%s %s(%s)", r.returnType(def.Returns), r.names.Name(naming.Method, def.Name), strings.Join(args, ", "))
+}
+
+// returnType returns the C# return type for a method, where multiple
+// return values are expressed as a tuple.
+func (r *renderer) returnType(returns []rewrite.ReturnDefinition) string {
+	switch len(returns) {
+	case 0:
+		return "void"
+	case 1:
+		return r.typeName(returns[0].Type)
+	}
+
+	var types = make([]string, 0, len(returns))
+	for _, ret := range returns {
+		types = append(types, r.typeName(ret.Type))
+	}
+	return fmt.Sprintf("(%s)", strings.Join(types, ", "))
+}
+
+// typeName returns the C# type for the giving type definition.
+func (r *renderer) typeName(definition rewrite.Applicable) string {
+	if definition == nil {
+		return "object"
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return r.baseType(def)
+	case rewrite.DataTypeDefinition:
+		// C# has no namespace level type aliases, so scalar aliases
+		// resolve to their type.
+		if def.Type != nil {
+			if _, ok := def.Type.Elem().(rewrite.TypeDefinition); ok {
+				return r.typeName(def.Type)
+			}
+		}
+		if def.Name == "" {
+			return r.typeName(def.Type)
+		}
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.DataDefinition:
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.FutureDefinition:
+		var task = r.use("System.Threading.Tasks", "Task")
+		if def.Type == nil {
+			return task
+		}
+		return fmt.Sprintf("%s<%s>", task, r.typeName(def.Type))
+	case rewrite.StreamDefinition:
+		return fmt.Sprintf("%s<%s>", r.use("System.Collections.Generic", "IAsyncEnumerable"), r.typeName(def.Type))
+	case rewrite.ChannelDefinition:
+		switch def.Direction {
+		case rewrite.IncomingDirectional:
+			return fmt.Sprintf("%s<%s>", r.use("System.Threading.Channels", "ChannelReader"), r.typeName(def.Type))
+		case rewrite.OutgoingDirectional:
+			return fmt.Sprintf("%s<%s>", r.use("System.Threading.Channels", "ChannelWriter"), r.typeName(def.Type))
+		default:
+			return fmt.Sprintf("%s<%s>", r.use("System.Threading.Channels", "Channel"), r.typeName(def.Type))
+		}
+	case rewrite.FieldDefinition:
+		return r.typeName(def.Type)
+	case rewrite.ReturnDefinition:
+		return r.typeName(def.Type)
+	}
+
+	r.SetErr(fmt.Errorf("csharp: %T is not a type definition", definition.Elem()))
+	return ""
+}
+
+func (r *renderer) baseType(def rewrite.TypeDefinition) string {
+	var typ, ok = r.types.Lookup(def)
+	if !ok {
+		r.SetErr(fmt.Errorf("csharp: base type %q has no C# equivalent", def.Type))
+		return ""
+	}
+	if typ.Import != "" {
+		r.imports[typ.Import] = true
+	}
+	return typ.Name
+}
@@ -0,0 +1,99 @@
+package csharp_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/csharp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "A registered user."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "Name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "Joined"}, Type: &rewrite.TypeDefinition{Type: rewrite.Time}},
+		},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "Models"},
+		Definitions: []rewrite.Applicable{
+			user,
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "IUsers"},
+				Methods: []rewrite.MethodDefinition{
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "Get"},
+						Arguments: []rewrite.FieldDefinition{
+							{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+						},
+						Returns: []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: user}}},
+					},
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "Watch"},
+						Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.StreamDefinition{Type: user}}},
+					},
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "Updates"},
+						Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.ChannelDefinition{Direction: rewrite.IncomingDirectional, Type: user}}},
+					},
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "Publish"},
+						Arguments: []rewrite.FieldDefinition{
+							{BaseDefinition: rewrite.BaseDefinition{Name: "users"}, Type: &rewrite.ChannelDefinition{Direction: rewrite.OutgoingDirectional, Type: user}},
+						},
+					},
+				},
+			},
+			&rewrite.VariableDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Limit"},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32},
+				Assign:         &rewrite.AssignmentDefinition{Value: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "10"}}},
+				Constant:       true,
+			},
+		},
+	}
+
+	var code, err = csharp.Render(pkg, csharp.Options{Namespace: "Example.Models"})
+	require.NoError(t, err)
+	require.Equal(t, `using System;
+using System.Collections.Generic;
+using System.Threading.Channels;
+using System.Threading.Tasks;
+
+namespace Example.Models;
+
+/// <summary>A registered user.</summary>
+public record User(string Name, DateTimeOffset Joined);
+
+public interface IUsers
+{
+    Task<User> Get(long id);
+
+    IAsyncEnumerable<User> Watch();
+
+    ChannelReader<User> Updates();
+
+    void Publish(ChannelWriter<User> users);
+}
+
+public static class Models
+{
+    public const int Limit = 10;
+}
+`, string(code))
+
+	code, err = csharp.Render(rewrite.PackageDefinition{Definitions: []rewrite.Applicable{user}}, csharp.Options{Classes: true})
+	require.NoError(t, err)
+	require.Equal(t, `using System;
+
+/// <summary>A registered user.</summary>
+public class User
+{
+    public string Name { get; set; }
+    public DateTimeOffset Joined { get; set; }
+}
+`, string(code))
+}
@@ -0,0 +1,345 @@
+// Package dart renders a rewrite.PackageDefinition into a Dart library of
+// immutable, JSON serializable classes for Flutter clients.
+package dart
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/naming"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+func init() {
+	typemap.Defaults("dart", typemap.Table{
+		{Type: rewrite.Rune}:    {Name: "String"},
+		{Type: rewrite.String}:  {Name: "String"},
+		{Type: rewrite.Integer}: {Name: "int"},
+		{Type: rewrite.Decimal}: {Name: "double"},
+		{Type: rewrite.Time}:    {Name: "DateTime"},
+	})
+	naming.Defaults("dart", naming.Convention{
+		Cases: map[naming.Construct]naming.Case{
+			naming.Type:     naming.Pascal,
+			naming.Field:    naming.Camel,
+			naming.Method:   naming.Camel,
+			naming.Constant: naming.Camel,
+		},
+		Keywords: naming.Set(
+			"assert", "break", "case", "catch", "class", "const", "continue",
+			"default", "do", "else", "enum", "extends", "false", "final",
+			"finally", "for", "if", "in", "is", "new", "null", "rethrow",
+			"return", "super", "switch", "this", "throw", "true", "try", "var",
+			"void", "while", "with",
+		),
+	})
+	generators.Register(generators.Single("dart", ".dart", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg)
+	}))
+}
+
+// Render returns the Dart source for the giving package definition.
+//
+// DataDefinition with fields are rendered as immutable classes with a
+// fromJson factory and toJson method, while those with only methods are
+// rendered as abstract classes. Base types are decoded following the Dart
+// type they map to: the JSON types are cast, double and DateTime are
+// converted, and any other mapped type, e.g an override mapping decimals to
+// a Decimal class, is expected to provide fromJson and toJson like the
+// rendered classes. FutureDefinition are rendered as Future,
+// StreamDefinition as Stream and ChannelDefinition as a StreamController,
+// Stream or StreamSink following its Direction. Statements found directly
+// within the package are ignored.
+func Render(pkg rewrite.PackageDefinition) ([]byte, error) {
+	var r = renderer{Renderer: generators.Renderer{DocFormat: "/// %s"}, imports: map[string]bool{}, types: typemap.For("dart"), names: naming.For("dart")}
+	for _, definition := range pkg.Definitions {
+		r.render(definition)
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	var out bytes.Buffer
+	if pkg.Description != "" {
+		fmt.Fprintf(&out, "%s\n", generators.Comment("// %s", pkg.Description))
+	}
+	if pkg.Version != "" {
+		fmt.Fprintf(&out, "// Version: %s\n", pkg.Version)
+	}
+	if pkg.Name != "" {
+		fmt.Fprintf(&out, "library %s;\n", pkg.Name)
+	}
+
+	var imports = make([]string, 0, len(r.imports))
+	for path := range r.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	if len(imports) != 0 && out.Len() != 0 {
+		out.WriteString("\n")
+	}
+	for _, path := range imports {
+		fmt.Fprintf(&out, "import '%s';\n", path)
+	}
+
+	out.Write(r.Out.Bytes())
+	return out.Bytes(), nil
+}
+
+type renderer struct {
+	generators.Renderer
+	types   typemap.Table
+	names   naming.Convention
+	imports map[string]bool
+}
+
+func (r *renderer) render(definition rewrite.Applicable) {
+	if definition == nil {
+		return
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.CommentDefinition:
+		r.Printf("\n")
+		for _, line := range def.Contents {
+			r.Printf("// %s\n", line)
+		}
+	case rewrite.DataDefinition:
+		if len(def.Fields) == 0 {
+			r.renderAbstract(def)
+			return
+		}
+		r.renderClass(def)
+	case rewrite.DataTypeDefinition:
+		r.Doc("", def.Description)
+		r.Printf("typedef %s = %s;\n", r.names.Name(naming.Type, def.Name), r.typeName(def.Type))
+	case rewrite.TypeDefinition:
+		r.Doc("", def.Description)
+		r.Printf("typedef %s = %s;\n", r.names.Name(naming.Type, def.Name), r.typeName(definition))
+	case rewrite.MethodDefinition:
+		r.Doc("", def.Description)
+		r.Printf("%s => throw UnimplementedError();\n", r.signature(def))
+	case rewrite.VariableDefinition:
+		r.renderVariable(def)
+	}
+}
+
+func (r *renderer) renderClass(def rewrite.DataDefinition) {
+	r.Doc("", def.Description)
+	// fields are encoded with their declared name, which is kept as the
+	// JSON key when the name of the field is converted.
+	var name = r.names.Name(naming.Type, def.Name)
+	r.Printf("class %s {\n", name)
+	for _, field := range def.Fields {
+		r.Doc("  ", field.Description)
+		r.Printf("  final %s %s;\n", r.typeName(field.Type), r.names.Name(naming.Field, field.Name))
+	}
+
+	r.Printf("\n  const %s({\n", name)
+	for _, field := range def.Fields {
+		r.Printf("    required this.%s,\n", r.names.Name(naming.Field, field.Name))
+	}
+	r.Printf("  });\n")
+
+	r.Printf("\n  factory %s.fromJson(Map<String, dynamic> json) => %s(\n", name, name)
+	for _, field := range def.Fields {
+		r.Printf("        %s: %s,\n", r.names.Name(naming.Field, field.Name), r.decode(field.Type, fmt.Sprintf("json['%s']", field.Name)))
+	}
+	r.Printf("      );\n")
+
+	r.Printf("\n  Map<String, dynamic> toJson() => {\n")
+	for _, field := range def.Fields {
+		r.Printf("        '%s': %s,\n", field.Name, r.encode(field.Type, r.names.Name(naming.Field, field.Name)))
+	}
+	r.Printf("      };\n")
+
+	for _, method := range def.Methods {
+		r.Printf("\n")
+		r.Doc("  ", method.Description)
+		r.Printf("  %s => throw UnimplementedError();\n", r.signature(method))
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderAbstract(def rewrite.DataDefinition) {
+	r.Doc("", def.Description)
+	r.Printf("abstract class %s {\n", r.names.Name(naming.Type, def.Name))
+	for index, method := range def.Methods {
+		if index != 0 {
+			r.Printf("\n")
+		}
+		r.Doc("  ", method.Description)
+		r.Printf("  %s;\n", r.signature(method))
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderVariable(def rewrite.VariableDefinition) {
+	var keyword = "var"
+	if def.Constant {
+		keyword = "const"
+	}
+
+	r.Doc("", def.Description)
+	r.Printf("%s ", keyword)
+	if def.Type != nil {
+		r.Printf("%s ", r.typeName(def.Type))
+	}
+	if def.Constant {
+		r.Printf("%s", r.names.Name(naming.Constant, def.Name))
+	} else {
+		r.Printf("%s", def.Name)
+	}
+	if def.Assign != nil && def.Assign.Value != nil {
+		r.Printf(" = %s", generators.Value(def.Assign.Value, "null"))
+	}
+	r.Printf(";\n")
+}
+
+// signature returns the return type, name and parameters of a method.
+func (r *renderer) signature(def rewrite.MethodDefinition) string {
+	var args = make([]string, 0, len(def.Arguments))
+	for _, arg := range def.Arguments {
+		args = append(args, fmt.Sprintf("%s %s", r.typeName(arg.Type), arg.Name))
+	}
+	return fmt.Sprintf("%s %s(%s)", r.returnType(def.Returns), r.names.Name(naming.Method, def.Name), strings.Join(args, ", "))
+}
+
+// returnType returns the Dart return type for a method, where multiple
+// return values are expressed as a record.
+func (r *renderer) returnType(returns []rewrite.ReturnDefinition) string {
+	switch len(returns) {
+	case 0:
+		return "void"
+	case 1:
+		return r.typeName(returns[0].Type)
+	}
+
+	var types = make([]string, 0, len(returns))
+	for _, ret := range returns {
+		types = append(types, r.typeName(ret.Type))
+	}
+	return fmt.Sprintf("(%s)", strings.Join(types, ", "))
+}
+
+// typeName returns the Dart type for the giving type definition.
+func (r *renderer) typeName(definition rewrite.Applicable) string {
+	if definition == nil {
+		return "dynamic"
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return r.baseType(def)
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return r.typeName(def.Type)
+		}
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.DataDefinition:
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.FutureDefinition:
+		if def.Type == nil {
+			return "Future<void>"
+		}
+		return fmt.Sprintf("Future<%s>", r.typeName(def.Type))
+	case rewrite.StreamDefinition:
+		return fmt.Sprintf("Stream<%s>", r.typeName(def.Type))
+	case rewrite.ChannelDefinition:
+		switch def.Direction {
+		case rewrite.IncomingDirectional:
+			return fmt.Sprintf("Stream<%s>", r.typeName(def.Type))
+		case rewrite.OutgoingDirectional:
+			r.imports["dart:async"] = true
+			return fmt.Sprintf("StreamSink<%s>", r.typeName(def.Type))
+		default:
+			r.imports["dart:async"] = true
+			return fmt.Sprintf("StreamController<%s>", r.typeName(def.Type))
+		}
+	case rewrite.FieldDefinition:
+		return r.typeName(def.Type)
+	case rewrite.ReturnDefinition:
+		return r.typeName(def.Type)
+	}
+
+	r.SetErr(fmt.Errorf("dart: %T is not a type definition", definition.Elem()))
+	return ""
+}
+
+func (r *renderer) baseType(def rewrite.TypeDefinition) string {
+	var typ, ok = r.types.Lookup(def)
+	if !ok {
+		r.SetErr(fmt.Errorf("dart: base type %q has no Dart equivalent", def.Type))
+		return ""
+	}
+	if typ.Import != "" {
+		r.imports[typ.Import] = true
+	}
+	return typ.Name
+}
+
+// decode returns the expression reading a field of the giving type from
+// its decoded JSON value.
+func (r *renderer) decode(definition rewrite.Applicable, expr string) string {
+	if definition == nil {
+		return expr
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		var name = r.baseType(def)
+		switch name {
+		case "double":
+			return fmt.Sprintf("(%s as num).toDouble()", expr)
+		case "DateTime":
+			return fmt.Sprintf("DateTime.parse(%s as String)", expr)
+		case "String", "int", "num", "bool", "dynamic", "":
+			return fmt.Sprintf("%s as %s", expr, name)
+		}
+		return fmt.Sprintf("%s.fromJson(%s)", name, expr)
+	case rewrite.DataTypeDefinition:
+		if def.Type != nil {
+			return r.decode(def.Type, expr)
+		}
+		return fmt.Sprintf("%s.fromJson(%s as Map<String, dynamic>)", r.names.Name(naming.Type, def.Name), expr)
+	case rewrite.DataDefinition:
+		return fmt.Sprintf("%s.fromJson(%s as Map<String, dynamic>)", r.names.Name(naming.Type, def.Name), expr)
+	}
+
+	r.SetErr(fmt.Errorf("dart: %T can not be decoded from JSON", definition.Elem()))
+	return ""
+}
+
+// encode returns the expression converting a field of the giving type
+// into its JSON value.
+func (r *renderer) encode(definition rewrite.Applicable, expr string) string {
+	if definition == nil {
+		return expr
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		switch r.baseType(def) {
+		case "DateTime":
+			return fmt.Sprintf("%s.toIso8601String()", expr)
+		case "double", "String", "int", "num", "bool", "dynamic", "":
+			return expr
+		}
+		return fmt.Sprintf("%s.toJson()", expr)
+	case rewrite.DataTypeDefinition:
+		if def.Type != nil {
+			return r.encode(def.Type, expr)
+		}
+		return fmt.Sprintf("%s.toJson()", expr)
+	case rewrite.DataDefinition:
+		return fmt.Sprintf("%s.toJson()", expr)
+	}
+
+	r.SetErr(fmt.Errorf("dart: %T can not be encoded to JSON", definition.Elem()))
+	return ""
+}
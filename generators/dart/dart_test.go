@@ -0,0 +1,153 @@
+package dart_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/dart"
+	"github.com/influx6/rewrite/generators/typemap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "A registered user."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "balance"}, Type: &rewrite.TypeDefinition{Type: rewrite.Decimal}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "joined"}, Type: &rewrite.TypeDefinition{Type: rewrite.Time}},
+		},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions: []rewrite.Applicable{
+			user,
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Users"},
+				Methods: []rewrite.MethodDefinition{
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "get"},
+						Arguments: []rewrite.FieldDefinition{
+							{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+						},
+						Returns: []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: user}}},
+					},
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "watch"},
+						Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.StreamDefinition{Type: user}}},
+					},
+				},
+			},
+		},
+	}
+
+	var code, err = dart.Render(pkg)
+	require.NoError(t, err)
+	require.Equal(t, `library models;
+
+/// A registered user.
+class User {
+  final String name;
+  final double balance;
+  final DateTime joined;
+
+  const User({
+    required this.name,
+    required this.balance,
+    required this.joined,
+  });
+
+  factory User.fromJson(Map<String, dynamic> json) => User(
+        name: json['name'] as String,
+        balance: (json['balance'] as num).toDouble(),
+        joined: DateTime.parse(json['joined'] as String),
+      );
+
+  Map<String, dynamic> toJson() => {
+        'name': name,
+        'balance': balance,
+        'joined': joined.toIso8601String(),
+      };
+}
+
+abstract class Users {
+  Future<User> get(int id);
+
+  Stream<User> watch();
+}
+`, string(code))
+}
+
+func TestRenderStreamField(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Feed"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "items"}, Type: &rewrite.StreamDefinition{Type: &rewrite.TypeDefinition{Type: rewrite.String}}},
+				},
+			},
+		},
+	}
+
+	var _, err = dart.Render(pkg)
+	require.Error(t, err)
+}
+
+func TestRenderRenamed(t *testing.T) {
+	var email = &rewrite.DataTypeDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "email_address"},
+		Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "accounts"},
+		Definitions: []rewrite.Applicable{
+			email,
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "account"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "email_address"}, Type: email},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "active"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+			},
+		},
+	}
+
+	var out, err = dart.Render(pkg)
+	require.NoError(t, err)
+	var code = string(out)
+	require.Contains(t, code, "typedef EmailAddress = String;\n")
+	require.Contains(t, code, "final EmailAddress emailAddress;\n")
+	require.Contains(t, code, "emailAddress: json['email_address'] as String,\n")
+	require.Contains(t, code, "'email_address': emailAddress,\n")
+}
+
+func TestRenderTypeOverride(t *testing.T) {
+	typemap.Override("dart", typemap.Key{Type: rewrite.Decimal}, typemap.Type{Name: "Decimal", Import: "package:decimal/decimal.dart"})
+	typemap.Override("dart", typemap.Key{Type: rewrite.Time}, typemap.Type{Name: "String"})
+	defer typemap.Reset("dart")
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "billing"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Invoice"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "total"}, Type: &rewrite.TypeDefinition{Type: rewrite.Decimal}},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "issued"}, Type: &rewrite.TypeDefinition{Type: rewrite.Time}},
+				},
+			},
+		},
+	}
+
+	var out, err = dart.Render(pkg)
+	require.NoError(t, err)
+	var code = string(out)
+	require.Contains(t, code, "import 'package:decimal/decimal.dart';\n")
+	require.Contains(t, code, "total: Decimal.fromJson(json['total']),\n")
+	require.Contains(t, code, "issued: json['issued'] as String,\n")
+	require.Contains(t, code, "'total': total.toJson(),\n")
+	require.Contains(t, code, "'issued': issued,\n")
+}
@@ -0,0 +1,323 @@
+// Package diagram renders the data model of a rewrite.PackageDefinition
+// into Graphviz DOT or Mermaid class diagrams.
+package diagram
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Format defines the diagram language of a rendered diagram.
+type Format int
+
+const (
+	DOT Format = iota
+	Mermaid
+)
+
+// Options configures how definitions are rendered.
+type Options struct {
+	Format Format
+}
+
+// node defines a class of the diagram.
+type node struct {
+	name       string
+	stereotype string
+	members    []string
+	methods    []string
+}
+
+// edge defines a reference from one node to another, labelled by the field
+// holding the reference.
+type edge struct {
+	from  string
+	to    string
+	label string
+}
+
+func init() {
+	typemap.Defaults("diagram", typemap.Table{
+		{Type: rewrite.String}:                         {Name: "string"},
+		{Type: rewrite.Rune}:                           {Name: "rune"},
+		{Type: rewrite.Integer}:                        {Name: "integer"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "integer32"},
+		{Type: rewrite.Decimal}:                        {Name: "decimal"},
+		{Type: rewrite.Decimal, Memory: rewrite.Bit32}: {Name: "decimal32"},
+		{Type: rewrite.Complex}:                        {Name: "complex"},
+		{Type: rewrite.Time}:                           {Name: "time"},
+	})
+	generators.Register(generators.Single("dot", ".dot", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg, Options{Format: DOT})
+	}))
+	generators.Register(generators.Single("mermaid", ".mmd", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg, Options{Format: Mermaid})
+	}))
+}
+
+// Render returns the class diagram for the giving package definition.
+//
+// DataDefinition are drawn as classes listing their fields and methods,
+// while named DataTypeDefinition are drawn as classes listing the type
+// they define. Edges are drawn for fields whose types refer to other
+// classes of the package, including those wrapped by a FutureDefinition,
+// StreamDefinition or ChannelDefinition.
+func Render(pkg rewrite.PackageDefinition, options Options) ([]byte, error) {
+	var open, close = "<", ">"
+	switch options.Format {
+	case DOT:
+	case Mermaid:
+		open, close = "~", "~"
+	default:
+		return nil, fmt.Errorf("diagram: unknown format %d", options.Format)
+	}
+
+	var r = renderer{open: open, close: close, declared: map[string]bool{}, types: typemap.For("diagram")}
+	for _, definition := range pkg.Definitions {
+		if definition == nil {
+			continue
+		}
+		switch def := definition.Elem().(type) {
+		case rewrite.DataDefinition:
+			r.declared[def.Name] = true
+		case rewrite.DataTypeDefinition:
+			if def.Name != "" {
+				r.declared[def.Name] = true
+			}
+		}
+	}
+
+	for _, definition := range pkg.Definitions {
+		if definition == nil {
+			continue
+		}
+		switch def := definition.Elem().(type) {
+		case rewrite.DataDefinition:
+			r.data(def)
+		case rewrite.DataTypeDefinition:
+			if def.Name != "" {
+				r.dataType(def)
+			}
+		}
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	var out bytes.Buffer
+	if options.Format == Mermaid {
+		writeMermaid(&out, pkg, r.nodes, r.edges)
+	} else {
+		writeDOT(&out, pkg, r.nodes, r.edges)
+	}
+	return out.Bytes(), nil
+}
+
+type renderer struct {
+	generators.Renderer
+	types    typemap.Table
+	open     string
+	close    string
+	declared map[string]bool
+	nodes    []node
+	edges    []edge
+}
+
+func (r *renderer) data(def rewrite.DataDefinition) {
+	var n = node{name: def.Name}
+	if len(def.Fields) == 0 {
+		n.stereotype = "interface"
+	}
+
+	for _, field := range def.Fields {
+		n.members = append(n.members, fmt.Sprintf("%s: %s", field.Name, r.typeName(field.Type)))
+		r.link(def.Name, field.Name, field.Type)
+	}
+
+	for _, method := range def.Methods {
+		var args = make([]string, 0, len(method.Arguments))
+		for _, arg := range method.Arguments {
+			args = append(args, fmt.Sprintf("%s: %s", arg.Name, r.typeName(arg.Type)))
+		}
+
+		var returns = make([]string, 0, len(method.Returns))
+		for _, ret := range method.Returns {
+			returns = append(returns, r.typeName(ret.Type))
+		}
+
+		var signature = fmt.Sprintf("%s(%s)", method.Name, strings.Join(args, ", "))
+		switch len(returns) {
+		case 0:
+		case 1:
+			signature += " " + returns[0]
+		default:
+			signature += " (" + strings.Join(returns, ", ") + ")"
+		}
+		n.methods = append(n.methods, signature)
+	}
+
+	r.nodes = append(r.nodes, n)
+}
+
+func (r *renderer) dataType(def rewrite.DataTypeDefinition) {
+	r.nodes = append(r.nodes, node{
+		name:       def.Name,
+		stereotype: "type",
+		members:    []string{r.typeName(def.Type)},
+	})
+	r.link(def.Name, "", def.Type)
+}
+
+// link adds an edge from a node to the node referenced by the giving type,
+// if the type refers to a class of the package.
+func (r *renderer) link(from string, label string, definition rewrite.Applicable) {
+	if definition == nil {
+		return
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			r.link(from, label, def.Type)
+			return
+		}
+		if r.declared[def.Name] {
+			r.edges = append(r.edges, edge{from: from, to: def.Name, label: label})
+		}
+	case rewrite.DataDefinition:
+		if r.declared[def.Name] {
+			r.edges = append(r.edges, edge{from: from, to: def.Name, label: label})
+		}
+	case rewrite.FutureDefinition:
+		r.link(from, label, def.Type)
+	case rewrite.StreamDefinition:
+		r.link(from, label, def.Type)
+	case rewrite.ChannelDefinition:
+		r.link(from, label, def.Type)
+	}
+}
+
+// typeName returns the language neutral notation of a type.
+func (r *renderer) typeName(definition rewrite.Applicable) string {
+	if definition == nil {
+		return "any"
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		var typ, ok = r.types.Lookup(def)
+		if !ok {
+			r.SetErr(fmt.Errorf("diagram: unknown base type %q", def.Type))
+		}
+		return typ.Name
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return r.typeName(def.Type)
+		}
+		return def.Name
+	case rewrite.DataDefinition:
+		return def.Name
+	case rewrite.FutureDefinition:
+		return "future" + r.open + r.typeName(def.Type) + r.close
+	case rewrite.StreamDefinition:
+		return "stream" + r.open + r.typeName(def.Type) + r.close
+	case rewrite.ChannelDefinition:
+		switch def.Direction {
+		case rewrite.IncomingDirectional:
+			return "receive channel" + r.open + r.typeName(def.Type) + r.close
+		case rewrite.OutgoingDirectional:
+			return "send channel" + r.open + r.typeName(def.Type) + r.close
+		}
+		return "channel" + r.open + r.typeName(def.Type) + r.close
+	case rewrite.FieldDefinition:
+		return r.typeName(def.Type)
+	case rewrite.ReturnDefinition:
+		return r.typeName(def.Type)
+	}
+
+	r.SetErr(fmt.Errorf("diagram: %T is not a type definition", definition.Elem()))
+	return ""
+}
+
+var dotEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"{", `\{`,
+	"}", `\}`,
+	"|", `\|`,
+	"<", `\<`,
+	">", `\>`,
+)
+
+func writeDOT(out *bytes.Buffer, pkg rewrite.PackageDefinition, nodes []node, edges []edge) {
+	fmt.Fprintf(out, "digraph %q {\n", pkg.Name)
+	out.WriteString("    node [shape=record];\n")
+
+	if len(nodes) != 0 {
+		out.WriteString("\n")
+	}
+	for _, n := range nodes {
+		var title = dotEscaper.Replace(n.name)
+		if n.stereotype != "" {
+			title = "«" + n.stereotype + "»\\n" + title
+		}
+
+		var compartments = []string{title}
+		for _, list := range [][]string{n.members, n.methods} {
+			var lines bytes.Buffer
+			for _, line := range list {
+				lines.WriteString(dotEscaper.Replace(line))
+				lines.WriteString(`\l`)
+			}
+			compartments = append(compartments, lines.String())
+		}
+		fmt.Fprintf(out, "    %q [label=\"{%s}\"];\n", n.name, strings.Join(compartments, "|"))
+	}
+
+	if len(edges) != 0 {
+		out.WriteString("\n")
+	}
+	for _, e := range edges {
+		if e.label == "" {
+			fmt.Fprintf(out, "    %q -> %q;\n", e.from, e.to)
+			continue
+		}
+		fmt.Fprintf(out, "    %q -> %q [label=%q];\n", e.from, e.to, e.label)
+	}
+	out.WriteString("}\n")
+}
+
+func writeMermaid(out *bytes.Buffer, pkg rewrite.PackageDefinition, nodes []node, edges []edge) {
+	out.WriteString("classDiagram\n")
+	if pkg.Description != "" {
+		fmt.Fprintf(out, "    %%%% %s\n", pkg.Description)
+	}
+
+	for _, n := range nodes {
+		fmt.Fprintf(out, "    class %s {\n", n.name)
+		if n.stereotype != "" {
+			fmt.Fprintf(out, "        <<%s>>\n", n.stereotype)
+		}
+		for _, member := range n.members {
+			fmt.Fprintf(out, "        +%s\n", member)
+		}
+		for _, method := range n.methods {
+			fmt.Fprintf(out, "        +%s\n", method)
+		}
+		out.WriteString("    }\n")
+	}
+
+	for _, e := range edges {
+		if e.label == "" {
+			fmt.Fprintf(out, "    %s --> %s\n", e.from, e.to)
+			continue
+		}
+		fmt.Fprintf(out, "    %s --> %s : %s\n", e.from, e.to, e.label)
+	}
+}
@@ -0,0 +1,90 @@
+package diagram_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/diagram"
+	"github.com/influx6/rewrite/generators/typemap"
+	"github.com/stretchr/testify/require"
+)
+
+var pkg = rewrite.PackageDefinition{
+	BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+	Definitions: []rewrite.Applicable{
+		&rewrite.DataTypeDefinition{
+			BaseDefinition: rewrite.BaseDefinition{Name: "ID"},
+			Type:           &rewrite.TypeDefinition{Type: rewrite.Integer},
+		},
+		&rewrite.DataDefinition{
+			BaseDefinition: rewrite.BaseDefinition{Name: "User"},
+			Fields: []rewrite.FieldDefinition{
+				{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "ID"}}},
+				{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				{BaseDefinition: rewrite.BaseDefinition{Name: "friends"}, Type: &rewrite.StreamDefinition{Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "User"}}}},
+			},
+			Methods: []rewrite.MethodDefinition{
+				{
+					BaseDefinition: rewrite.BaseDefinition{Name: "Rename"},
+					Arguments: []rewrite.FieldDefinition{
+						{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestRenderDOT(t *testing.T) {
+	var code, err = diagram.Render(pkg, diagram.Options{Format: diagram.DOT})
+	require.NoError(t, err)
+	require.Equal(t, `digraph "models" {
+    node [shape=record];
+
+    "ID" [label="{«type»\nID|integer\l|}"];
+    "User" [label="{User|id: ID\lname: string\lfriends: stream\<User\>\l|Rename(name: string)\l}"];
+
+    "User" -> "ID" [label="id"];
+    "User" -> "User" [label="friends"];
+}
+`, string(code))
+}
+
+func TestRenderMermaid(t *testing.T) {
+	var code, err = diagram.Render(pkg, diagram.Options{Format: diagram.Mermaid})
+	require.NoError(t, err)
+	require.Equal(t, `classDiagram
+    class ID {
+        <<type>>
+        +integer
+    }
+    class User {
+        +id: ID
+        +name: string
+        +friends: stream~User~
+        +Rename(name: string)
+    }
+    User --> ID : id
+    User --> User : friends
+`, string(code))
+}
+
+func TestRenderTypeOverride(t *testing.T) {
+	typemap.Override("diagram", typemap.Key{Type: rewrite.Decimal}, typemap.Type{Name: "Decimal"})
+	defer typemap.Reset("diagram")
+
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Invoice"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "total"}, Type: &rewrite.TypeDefinition{Type: rewrite.Decimal}},
+				},
+			},
+		},
+	}
+
+	var code, err = diagram.Render(pkg, diagram.Options{Format: diagram.Mermaid})
+	require.NoError(t, err)
+	require.Contains(t, string(code), "+total: Decimal\n")
+}
@@ -0,0 +1,374 @@
+// Package docs renders a rewrite.PackageDefinition into a browsable API
+// reference in Markdown or HTML.
+package docs
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Format defines the markup of a rendered reference.
+type Format int
+
+const (
+	Markdown Format = iota
+	HTML
+)
+
+// Options configures how definitions are rendered.
+type Options struct {
+	Format Format
+}
+
+func init() {
+	typemap.Defaults("docs", typemap.Table{
+		{Type: rewrite.String}:                         {Name: "string"},
+		{Type: rewrite.Rune}:                           {Name: "rune"},
+		{Type: rewrite.Integer}:                        {Name: "integer"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "integer32"},
+		{Type: rewrite.Decimal}:                        {Name: "decimal"},
+		{Type: rewrite.Decimal, Memory: rewrite.Bit32}: {Name: "decimal32"},
+		{Type: rewrite.Complex}:                        {Name: "complex"},
+		{Type: rewrite.Time}:                           {Name: "time"},
+	})
+	generators.Register(generators.Single("markdown", ".md", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg, Options{Format: Markdown})
+	}))
+	generators.Register(generators.Single("html", ".html", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg, Options{Format: HTML})
+	}))
+}
+
+// Render returns the API reference for the giving package definition.
+//
+// Each DataDefinition, named type, method and variable of the package gets
+// a section of its own, listing fields, method signatures and arguments
+// along with their descriptions and annotations. CommentDefinition are
+// rendered within the section of the declaration which follows them, and
+// references to declarations of the package link to their section.
+// Statements found directly within the package are ignored.
+func Render(pkg rewrite.PackageDefinition, options Options) ([]byte, error) {
+	var r = renderer{sections: map[string]string{}, anchors: map[string]bool{}, types: typemap.For("docs")}
+	switch options.Format {
+	case Markdown:
+		r.markup = markdown{}
+	case HTML:
+		r.markup = html{}
+	default:
+		return nil, fmt.Errorf("docs: unknown format %d", options.Format)
+	}
+
+	var title = "Package"
+	if pkg.Name != "" {
+		title = "Package " + pkg.Name
+	}
+
+	// sections get their anchors first, so methods never take them.
+	var contents []string
+	var anchors = make([]string, len(pkg.Definitions))
+	for index, definition := range pkg.Definitions {
+		if name := section(definition); name != "" {
+			anchors[index] = r.anchor(name)
+			if _, ok := r.sections[name]; !ok {
+				r.sections[name] = anchors[index]
+			}
+			contents = append(contents, r.markup.link(r.markup.escape(name), anchors[index]))
+		}
+	}
+
+	r.markup.begin(&r.Out, title)
+	r.markup.heading(&r.Out, 1, r.markup.escape(title), "")
+	if pkg.Description != "" {
+		r.markup.paragraph(&r.Out, r.markup.escape(pkg.Description))
+	}
+	if pkg.Version != "" {
+		r.markup.paragraph(&r.Out, "Version: "+r.markup.escape(pkg.Version))
+	}
+	if len(contents) != 0 {
+		r.markup.heading(&r.Out, 2, "Contents", "")
+		r.markup.list(&r.Out, contents)
+	}
+
+	var comments []string
+	for index, definition := range pkg.Definitions {
+		if definition == nil {
+			continue
+		}
+		if def, ok := definition.Elem().(rewrite.CommentDefinition); ok {
+			comments = append(comments, def.Contents...)
+			continue
+		}
+		if section(definition) == "" {
+			continue
+		}
+		r.render(definition, anchors[index], comments)
+		comments = nil
+	}
+	if len(comments) != 0 {
+		r.markup.heading(&r.Out, 2, "Notes", "")
+		r.comments(comments)
+	}
+
+	r.markup.end(&r.Out)
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return r.Out.Bytes(), nil
+}
+
+// section returns the name of the section of a definition, definitions
+// without a section return an empty name.
+func section(definition rewrite.Applicable) string {
+	if definition == nil {
+		return ""
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.DataDefinition:
+		return def.Name
+	case rewrite.DataTypeDefinition:
+		return def.Name
+	case rewrite.TypeDefinition:
+		return def.Name
+	case rewrite.MethodDefinition:
+		return def.Name
+	case rewrite.VariableDefinition:
+		return def.Name
+	}
+	return ""
+}
+
+type renderer struct {
+	generators.Renderer
+	types  typemap.Table
+	markup markup
+
+	// sections maps the names of declarations to the anchor of their
+	// section, and anchors holds the anchors taken so far.
+	sections map[string]string
+	anchors  map[string]bool
+}
+
+// anchor returns a new anchor for the giving name, the lower cased runs of
+// its letters and digits joined by "-", numbered when already taken, e.g
+// "User" and "user" get "user" and "user-2".
+func (r *renderer) anchor(name string) string {
+	var slug strings.Builder
+	var separate bool
+	for _, char := range strings.ToLower(name) {
+		if !unicode.IsLetter(char) && !unicode.IsDigit(char) {
+			separate = slug.Len() != 0
+			continue
+		}
+		if separate {
+			slug.WriteByte('-')
+			separate = false
+		}
+		slug.WriteRune(char)
+	}
+
+	var base = slug.String()
+	if base == "" {
+		base = "section"
+	}
+	var anchor = base
+	for number := 2; r.anchors[anchor]; number++ {
+		anchor = fmt.Sprintf("%s-%d", base, number)
+	}
+	r.anchors[anchor] = true
+	return anchor
+}
+
+func (r *renderer) render(definition rewrite.Applicable, anchor string, comments []string) {
+	switch def := definition.Elem().(type) {
+	case rewrite.DataDefinition:
+		r.header(anchor, def.Name, def.Description, comments, def.Annotations)
+		if len(def.Fields) != 0 {
+			r.markup.heading(&r.Out, 3, "Fields", "")
+			r.table("Name", def.Fields)
+		}
+		if len(def.Methods) != 0 {
+			r.markup.heading(&r.Out, 3, "Methods", "")
+		}
+		for _, method := range def.Methods {
+			r.markup.heading(&r.Out, 4, r.markup.escape(method.Name), r.anchor(def.Name+"-"+method.Name))
+			r.method(method)
+		}
+	case rewrite.DataTypeDefinition:
+		r.header(anchor, def.Name, def.Description, comments, nil)
+		r.markup.paragraph(&r.Out, "Type: "+r.typeName(def.Type))
+	case rewrite.TypeDefinition:
+		r.header(anchor, def.Name, def.Description, comments, nil)
+		r.markup.paragraph(&r.Out, "Type: "+r.typeName(&rewrite.TypeDefinition{Type: def.Type, Memory: def.Memory}))
+	case rewrite.MethodDefinition:
+		r.header(anchor, def.Name, "", comments, nil)
+		r.method(def)
+	case rewrite.VariableDefinition:
+		r.header(anchor, def.Name, def.Description, comments, nil)
+		if def.Type != nil {
+			r.markup.paragraph(&r.Out, "Type: "+r.typeName(def.Type))
+		}
+		if def.Assign != nil && def.Assign.Value != nil {
+			r.markup.paragraph(&r.Out, "Value: "+r.markup.code(generators.Value(def.Assign.Value, "nil")))
+		}
+	}
+}
+
+// header renders the heading of a section and its documentation.
+func (r *renderer) header(anchor string, name string, description string, comments []string, annotations rewrite.Annotations) {
+	r.markup.heading(&r.Out, 2, r.markup.escape(name), anchor)
+	if description != "" {
+		r.markup.paragraph(&r.Out, r.markup.escape(description))
+	}
+	r.comments(comments)
+	r.annotations(annotations)
+}
+
+func (r *renderer) comments(comments []string) {
+	if len(comments) != 0 {
+		r.markup.paragraph(&r.Out, r.markup.escape(strings.Join(comments, "\n")))
+	}
+}
+
+func (r *renderer) annotations(annotations rewrite.Annotations) {
+	if len(annotations) == 0 {
+		return
+	}
+
+	var items = make([]string, 0, len(annotations))
+	for _, annotation := range annotations {
+		items = append(items, r.annotation(annotation))
+	}
+	r.markup.paragraph(&r.Out, "Annotations:")
+	r.markup.list(&r.Out, items)
+}
+
+func (r *renderer) annotation(annotation rewrite.AnnotationDefinition) string {
+	if annotation.Content == "" {
+		return r.markup.code(annotation.Name)
+	}
+	return r.markup.code(annotation.Name) + ": " + r.markup.escape(annotation.Content)
+}
+
+// method renders the signature, documentation and arguments of a method.
+func (r *renderer) method(def rewrite.MethodDefinition) {
+	r.markup.block(&r.Out, r.signature(def))
+	if def.Description != "" {
+		r.markup.paragraph(&r.Out, r.markup.escape(def.Description))
+	}
+	r.annotations(def.Annotations)
+	if len(def.Arguments) != 0 {
+		r.table("Argument", def.Arguments)
+	}
+	if len(def.Returns) != 0 {
+		var types = make([]string, 0, len(def.Returns))
+		for _, ret := range def.Returns {
+			types = append(types, r.typeName(ret.Type))
+		}
+		r.markup.paragraph(&r.Out, "Returns: "+strings.Join(types, ", "))
+	}
+}
+
+// table renders fields as a table of name, type and description, where the
+// annotations of a field follow its description.
+func (r *renderer) table(title string, fields []rewrite.FieldDefinition) {
+	var rows = make([][]string, 0, len(fields))
+	for _, field := range fields {
+		var description = r.markup.escape(field.Description)
+		for _, annotation := range field.Annotations {
+			if description != "" {
+				description += " "
+			}
+			description += r.annotation(annotation)
+		}
+		rows = append(rows, []string{r.markup.escape(field.Name), r.typeName(field.Type), description})
+	}
+	r.markup.table(&r.Out, []string{title, "Type", "Description"}, rows)
+}
+
+// typeName returns the formatted type for the giving type definition, with
+// references to sections of the package linked.
+func (r *renderer) typeName(definition rewrite.Applicable) string {
+	return r.text(definition, func(name string) string {
+		if anchor, ok := r.sections[name]; ok {
+			return r.markup.link(r.markup.escape(name), anchor)
+		}
+		return r.markup.escape(name)
+	}, r.markup.escape)
+}
+
+// signature returns the plain text signature of a method.
+func (r *renderer) signature(def rewrite.MethodDefinition) string {
+	var plain = func(text string) string { return text }
+
+	var args = make([]string, 0, len(def.Arguments))
+	for _, arg := range def.Arguments {
+		args = append(args, fmt.Sprintf("%s %s", arg.Name, r.text(arg.Type, plain, plain)))
+	}
+
+	var returns = make([]string, 0, len(def.Returns))
+	for _, ret := range def.Returns {
+		returns = append(returns, r.text(ret.Type, plain, plain))
+	}
+
+	var signature = fmt.Sprintf("%s(%s)", def.Name, strings.Join(args, ", "))
+	switch len(returns) {
+	case 0:
+		return signature
+	case 1:
+		return fmt.Sprintf("%s %s", signature, returns[0])
+	}
+	return fmt.Sprintf("%s (%s)", signature, strings.Join(returns, ", "))
+}
+
+// text returns the language neutral notation of a type, name formats the
+// names of declared types and escape the rest of the notation.
+func (r *renderer) text(definition rewrite.Applicable, name func(string) string, escape func(string) string) string {
+	if definition == nil {
+		return escape("any")
+	}
+
+	var generic = func(kind string, elem rewrite.Applicable) string {
+		return escape(kind+"<") + r.text(elem, name, escape) + escape(">")
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		var typ, ok = r.types.Lookup(def)
+		if !ok {
+			r.SetErr(fmt.Errorf("docs: unknown base type %q", def.Type))
+		}
+		return escape(typ.Name)
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return r.text(def.Type, name, escape)
+		}
+		return name(def.Name)
+	case rewrite.DataDefinition:
+		return name(def.Name)
+	case rewrite.FutureDefinition:
+		return generic("future", def.Type)
+	case rewrite.StreamDefinition:
+		return generic("stream", def.Type)
+	case rewrite.ChannelDefinition:
+		switch def.Direction {
+		case rewrite.IncomingDirectional:
+			return generic("receive channel", def.Type)
+		case rewrite.OutgoingDirectional:
+			return generic("send channel", def.Type)
+		}
+		return generic("channel", def.Type)
+	case rewrite.FieldDefinition:
+		return r.text(def.Type, name, escape)
+	case rewrite.ReturnDefinition:
+		return r.text(def.Type, name, escape)
+	}
+
+	r.SetErr(fmt.Errorf("docs: %T is not a type definition", definition.Elem()))
+	return ""
+}
@@ -0,0 +1,141 @@
+package docs_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/docs"
+	"github.com/influx6/rewrite/generators/typemap"
+	"github.com/stretchr/testify/require"
+)
+
+var pkg = rewrite.PackageDefinition{
+	BaseDefinition: rewrite.BaseDefinition{Name: "models", Version: "1.0"},
+	Definitions: []rewrite.Applicable{
+		&rewrite.DataTypeDefinition{
+			BaseDefinition: rewrite.BaseDefinition{Name: "ID", Description: "Identifies a user."},
+			Type:           &rewrite.TypeDefinition{Type: rewrite.Integer},
+		},
+		&rewrite.CommentDefinition{Contents: []string{"Users are created on sign up."}},
+		&rewrite.DataDefinition{
+			BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "A registered user."},
+			Annotations:    rewrite.Annotations{{BaseDefinition: rewrite.BaseDefinition{Name: "table"}, Content: "users"}},
+			Fields: []rewrite.FieldDefinition{
+				{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "ID"}}},
+				{BaseDefinition: rewrite.BaseDefinition{Name: "name", Description: "The display name."}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			},
+			Methods: []rewrite.MethodDefinition{
+				{
+					BaseDefinition: rewrite.BaseDefinition{Name: "Friends", Description: "Lists friends of the user."},
+					Arguments: []rewrite.FieldDefinition{
+						{BaseDefinition: rewrite.BaseDefinition{Name: "limit", Description: "Maximum friends listed."}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32}},
+					},
+					Returns: []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "ID"}}}}},
+				},
+			},
+		},
+	},
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var doc, err = docs.Render(pkg, docs.Options{Format: docs.Markdown})
+	require.NoError(t, err)
+	require.Equal(t, "# Package models\n"+`
+Version: 1.0
+
+## Contents
+
+- [ID](#id)
+- [User](#user)
+
+<a id="id"></a>
+## ID
+
+Identifies a user.
+
+Type: integer
+
+<a id="user"></a>
+## User
+
+A registered user.
+
+Users are created on sign up.
+
+Annotations:
+
+`+"- `table`: users"+`
+
+### Fields
+
+| Name | Type | Description |
+| --- | --- | --- |
+| id | [ID](#id) |  |
+| name | string | The display name. |
+
+### Methods
+
+<a id="user-friends"></a>
+#### Friends
+`+"\n```\nFriends(limit integer32) future<ID>\n```\n"+`
+Lists friends of the user.
+
+| Argument | Type | Description |
+| --- | --- | --- |
+| limit | integer32 | Maximum friends listed. |
+
+Returns: future&lt;[ID](#id)&gt;
+`, string(doc))
+}
+
+func TestRenderHTML(t *testing.T) {
+	var doc, err = docs.Render(pkg, docs.Options{Format: docs.HTML})
+	require.NoError(t, err)
+	require.Contains(t, string(doc), `<li><a href="#user">User</a></li>`)
+	require.Contains(t, string(doc), `<h2 id="user">User</h2>`)
+	require.Contains(t, string(doc), `<tr><td>id</td><td><a href="#id">ID</a></td><td></td></tr>`)
+	require.Contains(t, string(doc), `<pre><code>Friends(limit integer32) future&lt;ID&gt;</code></pre>`)
+	require.Contains(t, string(doc), `<p>Returns: future&lt;<a href="#id">ID</a>&gt;</p>`)
+}
+
+func TestRenderTypeOverride(t *testing.T) {
+	typemap.Override("docs", typemap.Key{Type: rewrite.Decimal}, typemap.Type{Name: "Decimal"})
+	defer typemap.Reset("docs")
+
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.TypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "Total"}, Type: rewrite.Decimal},
+		},
+	}
+
+	var code, err = docs.Render(pkg, docs.Options{Format: docs.Markdown})
+	require.NoError(t, err)
+	require.Contains(t, string(code), "Type: Decimal\n")
+}
+
+func TestRenderAnchors(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "User"},
+				Methods:        []rewrite.MethodDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "Rename"}}},
+			},
+			&rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "user"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			&rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "User-Rename"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			&rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: `a"><script>`}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+		},
+	}
+
+	var code, err = docs.Render(pkg, docs.Options{Format: docs.HTML})
+	require.NoError(t, err)
+	require.Contains(t, string(code), `<li><a href="#user">User</a></li>
+<li><a href="#user-2">user</a></li>
+<li><a href="#user-rename">User-Rename</a></li>
+<li><a href="#a-script">a&#34;&gt;&lt;script&gt;</a></li>`)
+	require.Contains(t, string(code), `<h2 id="user">User</h2>`)
+	require.Contains(t, string(code), `<h2 id="user-2">user</h2>`)
+	require.Contains(t, string(code), `<h2 id="user-rename">User-Rename</h2>`)
+	require.Contains(t, string(code), `<h4 id="user-rename-2">Rename</h4>`)
+	require.Contains(t, string(code), `<h2 id="a-script">a&#34;&gt;&lt;script&gt;</h2>`)
+	require.NotContains(t, string(code), "<script>")
+}
@@ -0,0 +1,156 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	stdhtml "html"
+	"net/url"
+	"strings"
+)
+
+// markup writes the elements of a reference in a given Format, text given
+// to it is expected to be escaped already except for code and anchors,
+// which markup escapes itself.
+type markup interface {
+	escape(text string) string
+	code(text string) string
+	link(text string, anchor string) string
+
+	begin(out *bytes.Buffer, title string)
+	end(out *bytes.Buffer)
+	heading(out *bytes.Buffer, level int, text string, anchor string)
+	paragraph(out *bytes.Buffer, text string)
+	block(out *bytes.Buffer, code string)
+	list(out *bytes.Buffer, items []string)
+	table(out *bytes.Buffer, headers []string, rows [][]string)
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"|", `\|`,
+	"<", "&lt;",
+	">", "&gt;",
+)
+
+// markdown writes references as GitHub flavoured Markdown.
+type markdown struct{}
+
+func (markdown) escape(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+func (markdown) code(text string) string {
+	return "`" + strings.Replace(text, "`", "'", -1) + "`"
+}
+
+func (markdown) link(text string, anchor string) string {
+	return fmt.Sprintf("[%s](#%s)", text, url.PathEscape(anchor))
+}
+
+func (markdown) begin(out *bytes.Buffer, title string) {}
+
+func (markdown) end(out *bytes.Buffer) {}
+
+func (markdown) heading(out *bytes.Buffer, level int, text string, anchor string) {
+	if out.Len() != 0 {
+		out.WriteString("\n")
+	}
+	if anchor != "" {
+		fmt.Fprintf(out, "<a id=\"%s\"></a>\n", stdhtml.EscapeString(anchor))
+	}
+	fmt.Fprintf(out, "%s %s\n", strings.Repeat("#", level), text)
+}
+
+func (markdown) paragraph(out *bytes.Buffer, text string) {
+	fmt.Fprintf(out, "\n%s\n", strings.Replace(text, "\n", "  \n", -1))
+}
+
+func (markdown) block(out *bytes.Buffer, code string) {
+	fmt.Fprintf(out, "\n```\n%s\n```\n", code)
+}
+
+func (markdown) list(out *bytes.Buffer, items []string) {
+	out.WriteString("\n")
+	for _, item := range items {
+		fmt.Fprintf(out, "- %s\n", item)
+	}
+}
+
+func (markdown) table(out *bytes.Buffer, headers []string, rows [][]string) {
+	var rule = make([]string, len(headers))
+	for index := range rule {
+		rule[index] = "---"
+	}
+
+	fmt.Fprintf(out, "\n| %s |\n", strings.Join(headers, " | "))
+	fmt.Fprintf(out, "| %s |\n", strings.Join(rule, " | "))
+	for _, row := range rows {
+		fmt.Fprintf(out, "| %s |\n", strings.Join(row, " | "))
+	}
+}
+
+// html writes references as a standalone HTML document.
+type html struct{}
+
+func (html) escape(text string) string {
+	return stdhtml.EscapeString(text)
+}
+
+func (html) code(text string) string {
+	return "<code>" + stdhtml.EscapeString(text) + "</code>"
+}
+
+func (html) link(text string, anchor string) string {
+	return fmt.Sprintf("<a href=\"#%s\">%s</a>", stdhtml.EscapeString(anchor), text)
+}
+
+func (html) begin(out *bytes.Buffer, title string) {
+	fmt.Fprintf(out, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n", stdhtml.EscapeString(title))
+}
+
+func (html) end(out *bytes.Buffer) {
+	out.WriteString("</body>\n</html>\n")
+}
+
+func (html) heading(out *bytes.Buffer, level int, text string, anchor string) {
+	if anchor != "" {
+		fmt.Fprintf(out, "<h%d id=\"%s\">%s</h%d>\n", level, stdhtml.EscapeString(anchor), text, level)
+		return
+	}
+	fmt.Fprintf(out, "<h%d>%s</h%d>\n", level, text, level)
+}
+
+func (html) paragraph(out *bytes.Buffer, text string) {
+	fmt.Fprintf(out, "<p>%s</p>\n", strings.Replace(text, "\n", "<br>\n", -1))
+}
+
+func (html) block(out *bytes.Buffer, code string) {
+	fmt.Fprintf(out, "<pre><code>%s</code></pre>\n", stdhtml.EscapeString(code))
+}
+
+func (html) list(out *bytes.Buffer, items []string) {
+	out.WriteString("<ul>\n")
+	for _, item := range items {
+		fmt.Fprintf(out, "<li>%s</li>\n", item)
+	}
+	out.WriteString("</ul>\n")
+}
+
+func (html) table(out *bytes.Buffer, headers []string, rows [][]string) {
+	out.WriteString("<table>\n<tr>")
+	for _, header := range headers {
+		fmt.Fprintf(out, "<th>%s</th>", header)
+	}
+	out.WriteString("</tr>\n")
+	for _, row := range rows {
+		out.WriteString("<tr>")
+		for _, cell := range row {
+			fmt.Fprintf(out, "<td>%s</td>", cell)
+		}
+		out.WriteString("</tr>\n")
+	}
+	out.WriteString("</table>\n")
+}
@@ -0,0 +1,58 @@
+package generators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/influx6/rewrite"
+)
+
+// FieldNumbers defines how the fields of a wire format are numbered, e.g
+// the field numbers of protobuf or the field ids of thrift.
+//
+// Numbers identify fields on the wire, so they are never derived from the
+// position of a field, as declaring a field before others would renumber
+// them. Every field pins its number with an annotation instead, and
+// removed fields should keep their number reserved.
+type FieldNumbers struct {
+	// Annotation is the name of the annotation pinning the number of a
+	// field, its content being the number, e.g "3".
+	Annotation string
+
+	// Max is the highest valid number.
+	Max int
+
+	// Reserved holds the inclusive ranges of numbers reserved by the
+	// format, which fields can not use.
+	Reserved [][2]int
+}
+
+// Numbers returns the pinned number of each field, it returns an error if
+// a field has no valid number or reuses the number of another field.
+func (n FieldNumbers) Numbers(fields []rewrite.FieldDefinition) ([]int, error) {
+	var numbers = make([]int, len(fields))
+	var used = map[int]string{}
+	for index, field := range fields {
+		var annotation, ok = field.Annotations.Get(n.Annotation)
+		if !ok {
+			return nil, fmt.Errorf("field %q requires a %s annotation", field.Name, n.Annotation)
+		}
+
+		var number, err = strconv.Atoi(strings.TrimSpace(annotation.Content))
+		if err != nil || number < 1 || number > n.Max {
+			return nil, fmt.Errorf("field %q has invalid %s %q, it must be between 1 and %d", field.Name, n.Annotation, annotation.Content, n.Max)
+		}
+		for _, reserved := range n.Reserved {
+			if number >= reserved[0] && number <= reserved[1] {
+				return nil, fmt.Errorf("field %q uses %s %d reserved by the format (%d to %d)", field.Name, n.Annotation, number, reserved[0], reserved[1])
+			}
+		}
+		if other, ok := used[number]; ok {
+			return nil, fmt.Errorf("field %q reuses %s %d of field %q", field.Name, n.Annotation, number, other)
+		}
+		used[number] = field.Name
+		numbers[index] = number
+	}
+	return numbers, nil
+}
@@ -0,0 +1,37 @@
+package generators_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/stretchr/testify/require"
+)
+
+func field(name string, number string) rewrite.FieldDefinition {
+	var field = rewrite.FieldDefinition{BaseDefinition: rewrite.BaseDefinition{Name: name}}
+	if number != "" {
+		field.Annotations = rewrite.Annotations{{BaseDefinition: rewrite.BaseDefinition{Name: "number"}, Content: number}}
+	}
+	return field
+}
+
+func TestFieldNumbers(t *testing.T) {
+	var numbering = generators.FieldNumbers{Annotation: "number", Max: 100, Reserved: [][2]int{{50, 59}}}
+
+	var numbers, err = numbering.Numbers([]rewrite.FieldDefinition{field("id", "2"), field("name", " 1 ")})
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 1}, numbers)
+
+	for _, fields := range [][]rewrite.FieldDefinition{
+		{field("id", "")},
+		{field("id", "0")},
+		{field("id", "101")},
+		{field("id", "first")},
+		{field("id", "55")},
+		{field("id", "1"), field("name", "1")},
+	} {
+		_, err = numbering.Numbers(fields)
+		require.Error(t, err)
+	}
+}
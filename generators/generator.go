@@ -0,0 +1,165 @@
+package generators
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/influx6/rewrite"
+)
+
+// File defines a file produced by a Generator.
+type File struct {
+	// Name is the path of the file, relative to the output directory.
+	Name    string
+	Content []byte
+}
+
+// Generator defines a backend which renders a package definition into
+// the source files of a target language or format.
+type Generator interface {
+	// Name returns the name the generator is registered with, e.g "go".
+	Name() string
+
+	// Extensions returns the file extensions of produced files, e.g ".go".
+	Extensions() []string
+
+	// Generate returns the files rendered for the giving package definition.
+	Generate(pkg rewrite.PackageDefinition) ([]File, error)
+}
+
+// New returns a Generator which renders packages with generate.
+func New(name string, extensions []string, generate func(rewrite.PackageDefinition) ([]File, error)) Generator {
+	return generator{name: name, extensions: extensions, generate: generate}
+}
+
+// Single returns a Generator for backends rendering a package into a
+// single file, named after the package with the giving extension.
+func Single(name string, extension string, render func(rewrite.PackageDefinition) ([]byte, error)) Generator {
+	return New(name, []string{extension}, func(pkg rewrite.PackageDefinition) ([]File, error) {
+		var content, err = render(pkg)
+		if err != nil {
+			return nil, err
+		}
+		return []File{{Name: FileName(pkg, extension), Content: content}}, nil
+	})
+}
+
+// Multiple returns a Generator for backends rendering a package into
+// several files keyed by file name, files are ordered by name.
+func Multiple(name string, extensions []string, render func(rewrite.PackageDefinition) (map[string][]byte, error)) Generator {
+	return New(name, extensions, func(pkg rewrite.PackageDefinition) ([]File, error) {
+		var contents, err = render(pkg)
+		if err != nil {
+			return nil, err
+		}
+
+		var files = make([]File, 0, len(contents))
+		for name, content := range contents {
+			files = append(files, File{Name: name, Content: content})
+		}
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].Name < files[j].Name
+		})
+		return files, nil
+	})
+}
+
+// FileName returns the name of the file holding a whole package, which is
+// the package name with the giving extension.
+func FileName(pkg rewrite.PackageDefinition, extension string) string {
+	if pkg.Name == "" {
+		return "package" + extension
+	}
+	return pkg.Name + extension
+}
+
+type generator struct {
+	name       string
+	extensions []string
+	generate   func(rewrite.PackageDefinition) ([]File, error)
+}
+
+func (g generator) Name() string {
+	return g.name
+}
+
+func (g generator) Extensions() []string {
+	return g.extensions
+}
+
+func (g generator) Generate(pkg rewrite.PackageDefinition) ([]File, error) {
+	return g.generate(pkg)
+}
+
+// Registry holds generators by name, it is safe for concurrent use.
+type Registry struct {
+	mu         sync.RWMutex
+	generators map[string]Generator
+}
+
+// NewRegistry returns a new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{generators: map[string]Generator{}}
+}
+
+// Register adds the generator to the registry, it returns an error if a
+// generator with the same name is already registered.
+func (r *Registry) Register(generator Generator) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var name = generator.Name()
+	if name == "" {
+		return fmt.Errorf("generators: generator requires a name")
+	}
+	if _, ok := r.generators[name]; ok {
+		return fmt.Errorf("generators: generator %q is already registered", name)
+	}
+	r.generators[name] = generator
+	return nil
+}
+
+// Get returns the generator registered with the giving name.
+func (r *Registry) Get(name string) (Generator, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if generator, ok := r.generators[name]; ok {
+		return generator, nil
+	}
+	return nil, fmt.Errorf("generators: no generator registered as %q", name)
+}
+
+// Names returns the sorted names of registered generators.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var names = make([]string, 0, len(r.generators))
+	for name := range r.generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Generators is the default registry. Backends of this module register
+// themselves into it when their package is imported, so importing
+// e.g "github.com/influx6/rewrite/generators/kotlin" for its side
+// effects makes the "kotlin" generator available.
+var Generators = NewRegistry()
+
+// Register adds the generator to the default registry, panicking if a
+// generator with the same name is already registered.
+func Register(generator Generator) {
+	if err := Generators.Register(generator); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the generator registered in the default registry with
+// the giving name.
+func Lookup(name string) (Generator, error) {
+	return Generators.Get(name)
+}
@@ -0,0 +1,71 @@
+package generators_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	_ "github.com/influx6/rewrite/generators/java"
+	_ "github.com/influx6/rewrite/generators/kotlin"
+	"github.com/stretchr/testify/require"
+)
+
+var models = rewrite.PackageDefinition{
+	BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+	Definitions: []rewrite.Applicable{
+		&rewrite.DataDefinition{
+			BaseDefinition: rewrite.BaseDefinition{Name: "User"},
+			Fields: []rewrite.FieldDefinition{
+				{BaseDefinition: rewrite.BaseDefinition{Name: "Name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			},
+		},
+	},
+}
+
+func TestLookup(t *testing.T) {
+	var golang, err = generators.Lookup("go")
+	require.NoError(t, err)
+	require.Equal(t, []string{".go"}, golang.Extensions())
+
+	files, err := golang.Generate(models)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "models.go", files[0].Name)
+	require.Contains(t, string(files[0].Content), "type User struct")
+
+	kotlin, err := generators.Lookup("kotlin")
+	require.NoError(t, err)
+	files, err = kotlin.Generate(models)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "models.kt", files[0].Name)
+
+	java, err := generators.Lookup("java")
+	require.NoError(t, err)
+	files, err = java.Generate(models)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "User.java", files[0].Name)
+
+	_, err = generators.Lookup("cobol")
+	require.Error(t, err)
+}
+
+func TestRegistry(t *testing.T) {
+	var registry = generators.NewRegistry()
+	var text = generators.Single("text", ".txt", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return []byte(pkg.Name), nil
+	})
+
+	require.NoError(t, registry.Register(text))
+	require.Error(t, registry.Register(text))
+	require.NoError(t, registry.Register(generators.Go))
+	require.Equal(t, []string{"go", "text"}, registry.Names())
+
+	var generator, err = registry.Get("text")
+	require.NoError(t, err)
+
+	files, err := generator.Generate(models)
+	require.NoError(t, err)
+	require.Equal(t, []generators.File{{Name: "models.txt", Content: []byte("models")}}, files)
+}
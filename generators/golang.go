@@ -1,56 +1,640 @@
 package generators
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
 	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/naming"
+	"github.com/influx6/rewrite/generators/typemap"
 )
-import "github.com/dave/jennifer/jen"
 
-func Render(pkg rewrite.PackageDefinition) *jen.File {
+// Go generates the Go source of a package into a single file, resolving
+// references to other packages of a Project into imports.
+var Go ProjectGenerator = golangGenerator{}
+
+func init() {
+	Register(Go)
+	typemap.Defaults("go", typemap.Table{
+		{Type: rewrite.String}:                         {Name: "string"},
+		{Type: rewrite.Rune}:                           {Name: "rune"},
+		{Type: rewrite.Decimal}:                        {Name: "float64"},
+		{Type: rewrite.Decimal, Memory: rewrite.Bit32}: {Name: "float32"},
+		{Type: rewrite.Integer}:                        {Name: "int64"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "int32"},
+		{Type: rewrite.Complex}:                        {Name: "complex128"},
+		{Type: rewrite.Complex, Memory: rewrite.Bit32}: {Name: "complex64"},
+		{Type: rewrite.Time}:                           {Name: "Time", Import: "time"},
+	})
+
+	// Only fields are converted by default, as the case of types, methods
+	// and constants decides whether they are exported.
+	naming.Defaults("go", naming.Convention{
+		Cases: map[naming.Construct]naming.Case{naming.Field: naming.Pascal},
+		Keywords: naming.Set(
+			"break", "case", "chan", "const", "continue", "default", "defer",
+			"else", "fallthrough", "for", "func", "go", "goto", "if", "import",
+			"interface", "map", "package", "range", "return", "select",
+			"struct", "switch", "type", "var",
+		),
+		Initialisms: naming.Set(
+			"acl", "api", "ascii", "cpu", "css", "dns", "eof", "guid", "html",
+			"http", "https", "id", "ip", "json", "lhs", "qps", "ram", "rhs",
+			"rpc", "sla", "smtp", "sql", "ssh", "tcp", "tls", "ttl", "udp",
+			"ui", "uid", "uri", "url", "utf8", "uuid", "vm", "xml", "xmpp",
+			"xsrf", "xss",
+		),
+	})
+}
+
+type golangGenerator struct{}
+
+func (golangGenerator) Name() string {
+	return "go"
+}
+
+func (golangGenerator) Extensions() []string {
+	return []string{".go"}
+}
+
+func (g golangGenerator) Generate(pkg rewrite.PackageDefinition) ([]File, error) {
+	return g.GenerateImports(pkg, nil)
+}
+
+func (golangGenerator) GenerateImports(pkg rewrite.PackageDefinition, imports Imports) ([]File, error) {
+	var file, err = RenderImports(pkg, imports)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := file.Render(&out); err != nil {
+		return nil, err
+	}
+	return []File{{Name: FileName(pkg, ".go"), Content: out.Bytes()}}, nil
+}
+
+// Render returns the Go source for the giving package definition.
+//
+// Declarations (data, types, methods, variables and constants) are rendered
+// at package level, while statements found directly within the package
+// (if, for, switch, assignments, calls) are collected into an init function,
+// as Go does not allow them outside of a function body.
+//
+// Methods without a body are rendered as stubs returning the zero values
+// of their results, and unnamed types or unknown operators are reported
+// as errors.
+func Render(pkg rewrite.PackageDefinition) (*jen.File, error) {
+	return RenderImports(pkg, nil)
+}
+
+// RenderImports returns the Go source for the giving package definition like
+// Render, where references qualified with the name of a package in imports,
+// e.g "models.User", are rendered with the import of that package.
+func RenderImports(pkg rewrite.PackageDefinition, imports Imports) (*jen.File, error) {
+	var g = &golang{pkg: pkg.Name, imports: imports, types: typemap.For("go"), names: naming.For("go")}
 	var code = jen.NewFile(pkg.GetName())
-	code.Comment(pkg.GetDescription())
-	code.Comment("\n")
-	code.Comment("Version: ")
-	code.Comment(pkg.Version)
+	for _, line := range commentLines(pkg.Description) {
+		code.PackageComment(line)
+	}
+	if pkg.Version != "" {
+		code.PackageComment("Version: " + pkg.Version)
+	}
 
+	var statements []jen.Code
 	for _, definition := range pkg.Definitions {
-		render(code, definition)
+		if !g.render(code, definition) {
+			statements = append(statements, g.renderStatement(definition))
+		}
+	}
+
+	if len(statements) != 0 {
+		code.Func().Id("init").Params().Block(statements...)
+	}
+
+	if g.err != nil {
+		return nil, g.err
+	}
+	return code, nil
+}
+
+// golang renders definitions into Go code.
+type golang struct {
+	pkg     string
+	imports Imports
+	types   typemap.Table
+	names   naming.Convention
+
+	// err holds the first error met while rendering.
+	err error
+}
+
+// setErr records err unless an earlier error was recorded.
+func (g *golang) setErr(err error) {
+	if g.err == nil {
+		g.err = err
 	}
-	return code
 }
 
-func render(file *jen.File, definition rewrite.Applicable) {
+// render renders the definition as a package level declaration, it
+// returns false if the definition is a statement and must be
+// rendered within a function body.
+func (g *golang) render(file *jen.File, definition rewrite.Applicable) bool {
+	if definition == nil {
+		return true
+	}
+
 	switch def := definition.Elem().(type) {
 	case rewrite.VariableDefinition:
-		renderVariable(file, def)
-	case rewrite.AssignmentDefinition:
-		renderAssignment(file, def)
+		g.renderVariable(file, def)
 	case rewrite.AnnotationDefinition:
+		file.Comment(Annotation(def))
 	case rewrite.CommentDefinition:
-	case rewrite.ResultDefinition:
+		for _, line := range commentLines(def.Contents...) {
+			file.Comment(line)
+		}
 	case rewrite.DataDefinition:
+		g.renderData(file, def)
 	case rewrite.DataTypeDefinition:
-	case rewrite.ConditionDefinition:
-	case rewrite.IfDefinition:
+		if def.Name == "" {
+			g.setErr(fmt.Errorf("go: data type declaration requires a name"))
+			return true
+		}
+		comment(file.Group, def.Description)
+		file.Type().Id(g.names.Name(naming.Type, def.Name)).Add(g.renderType(def.Type))
+	case rewrite.TypeDefinition:
+		if def.Name == "" {
+			g.setErr(fmt.Errorf("go: type declaration of base type %q requires a name", def.Type))
+			return true
+		}
+		comment(file.Group, def.Description)
+		file.Type().Id(g.names.Name(naming.Type, def.Name)).Add(g.renderType(definition))
+	case rewrite.MethodDefinition:
+		g.renderMethod(file, nil, def)
+	case rewrite.FieldDefinition:
+		comment(file.Group, def.Description)
+		file.Var().Id(g.names.Name(naming.Variable, def.Name)).Add(g.renderType(def.Type))
+	case rewrite.ResultDefinition, rewrite.ReturnDefinition, rewrite.CaseDefinition:
+		// results, returns and cases are only meaningful within
+		// a method or switch, so they have nothing to declare.
+	default:
+		return false
+	}
+	return true
+}
+
+func (g *golang) renderVariable(file *jen.File, def rewrite.VariableDefinition) {
+	comment(file.Group, def.Description)
+	file.Add(g.renderVariableDeclaration(def))
+}
+
+func (g *golang) renderVariableDeclaration(def rewrite.VariableDefinition) *jen.Statement {
+	var declaration = jen.Var()
+	if def.Constant {
+		declaration = jen.Const()
+	}
+
+	if def.Constant {
+		declaration.Id(g.names.Name(naming.Constant, def.Name))
+	} else {
+		declaration.Id(g.names.Name(naming.Variable, def.Name))
+	}
+	if def.Type != nil {
+		declaration.Add(g.renderType(def.Type))
+	}
+	if def.Assign != nil && def.Assign.Value != nil {
+		declaration.Op("=").Add(g.renderExpr(def.Assign.Value))
+	}
+	return declaration
+}
+
+func (g *golang) renderAssignment(def rewrite.AssignmentDefinition) *jen.Statement {
+	var operator = "="
+	if def.Short {
+		operator = ":="
+	}
+	return jen.Id(g.names.Name(naming.Variable, def.Name)).Op(operator).Add(g.renderExpr(def.Value))
+}
+
+func (g *golang) renderData(file *jen.File, def rewrite.DataDefinition) {
+	comment(file.Group, def.Description)
+	file.Type().Id(g.names.Name(naming.Type, def.Name)).StructFunc(func(fields *jen.Group) {
+		for _, field := range def.Fields {
+			comment(fields, field.Description)
+
+			// fields whose name is converted keep their declared name
+			// when encoded.
+			var name = g.names.Name(naming.Field, field.Name)
+			var declaration = fields.Id(name).Add(g.renderType(field.Type))
+			if name != field.Name {
+				declaration.Tag(map[string]string{"json": field.Name})
+			}
+		}
+	})
+
+	for _, method := range def.Methods {
+		g.renderMethod(file, &def, method)
+	}
+}
+
+func (g *golang) renderMethod(file *jen.File, owner *rewrite.DataDefinition, def rewrite.MethodDefinition) {
+	comment(file.Group, def.Description)
+
+	var method = jen.Func()
+	if owner != nil {
+		var name = g.names.Name(naming.Type, owner.Name)
+		method.Params(jen.Id(receiverName(name)).Op("*").Id(name))
+	}
+
+	method.Id(g.names.Name(naming.Method, def.Name)).Add(g.renderSignature(def))
+
+	if def.Data == nil {
+		method.Block(g.renderStub(def)...)
+	} else {
+		method.Block(g.renderBody(def.Data)...)
+	}
+
+	file.Add(method)
+}
+
+// renderStub renders the body of a method without one, which returns the
+// zero values of its results for the method to be implemented.
+func (g *golang) renderStub(def rewrite.MethodDefinition) []jen.Code {
+	if len(def.Returns) == 0 {
+		return nil
+	}
+
+	var body = []jen.Code{jen.Comment("TODO: implement " + g.names.Name(naming.Method, def.Name) + ".")}
+	if namedReturns(def.Returns) {
+		return append(body, jen.Return())
+	}
+	return append(body, jen.ReturnFunc(func(values *jen.Group) {
+		for _, ret := range def.Returns {
+			values.Op("*").New(g.renderType(ret.Type))
+		}
+	}))
+}
+
+// renderSignature renders the argument and return list of a method.
+func (g *golang) renderSignature(def rewrite.MethodDefinition) *jen.Statement {
+	var signature = jen.ParamsFunc(func(args *jen.Group) {
+		for _, arg := range def.Arguments {
+			args.Id(g.names.Name(naming.Variable, arg.Name)).Add(g.renderType(arg.Type))
+		}
+	})
+
+	if len(def.Returns) == 0 {
+		return signature
+	}
+
+	var named = namedReturns(def.Returns)
+	if len(def.Returns) == 1 && !named {
+		return signature.Add(g.renderType(def.Returns[0].Type))
+	}
+
+	return signature.ParamsFunc(func(returns *jen.Group) {
+		for _, ret := range def.Returns {
+			if named {
+				returns.Id(g.names.Name(naming.Variable, ret.Name)).Add(g.renderType(ret.Type))
+				continue
+			}
+			returns.Add(g.renderType(ret.Type))
+		}
+	})
+}
+
+// renderType renders the Go type for the giving type definition.
+func (g *golang) renderType(definition rewrite.Applicable) *jen.Statement {
+	if definition == nil {
+		return jen.Interface()
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return g.renderBaseType(def)
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return g.renderType(def.Type)
+		}
+		return g.renderName(def.Name)
+	case rewrite.DataDefinition:
+		return g.renderName(def.Name)
+	case rewrite.ChannelDefinition:
+		switch def.Direction {
+		case rewrite.IncomingDirectional:
+			return jen.Op("<-").Chan().Add(g.renderType(def.Type))
+		case rewrite.OutgoingDirectional:
+			return jen.Chan().Op("<-").Add(g.renderType(def.Type))
+		default:
+			return jen.Chan().Add(g.renderType(def.Type))
+		}
+	case rewrite.FutureDefinition:
+		return jen.Op("<-").Chan().Add(g.renderType(def.Type))
+	case rewrite.StreamDefinition:
+		return jen.Op("<-").Chan().Add(g.renderType(def.Type))
+	case rewrite.MethodDefinition:
+		return jen.Func().Add(g.renderSignature(def))
+	case rewrite.FieldDefinition:
+		return g.renderType(def.Type)
 	case rewrite.ReturnDefinition:
+		return g.renderType(def.Type)
+	}
+	return jen.Interface()
+}
+
+// renderName renders a reference to a declared type, qualified references
+// to an imported package are rendered with the import of the package and
+// references qualified with the rendered package are unqualified.
+func (g *golang) renderName(name string) *jen.Statement {
+	if index := strings.Index(name, "."); index != -1 {
+		if name[:index] == g.pkg {
+			return jen.Id(g.names.Name(naming.Type, name[index+1:]))
+		}
+		if path, ok := g.imports[name[:index]]; ok {
+			return jen.Qual(path, g.names.Name(naming.Type, name[index+1:]))
+		}
+	}
+	return jen.Id(g.names.Name(naming.Type, name))
+}
+
+func (g *golang) renderBaseType(def rewrite.TypeDefinition) *jen.Statement {
+	var typ, ok = g.types.Lookup(def)
+	if !ok {
+		return jen.Interface()
+	}
+	if typ.Import != "" {
+		return jen.Qual(typ.Import, typ.Name)
+	}
+	return jen.Id(typ.Name)
+}
+
+// renderBody renders the statements of a method or control flow body,
+// where a BlockDefinition provides multiple statements.
+func (g *golang) renderBody(definition rewrite.Applicable) []jen.Code {
+	if definition == nil {
+		return nil
+	}
+
+	if block, ok := definition.Elem().(rewrite.BlockDefinition); ok {
+		var statements = make([]jen.Code, 0, len(block.Statements))
+		for _, statement := range block.Statements {
+			statements = append(statements, g.renderStatement(statement))
+		}
+		return statements
+	}
+	return []jen.Code{g.renderStatement(definition)}
+}
+
+func (g *golang) renderStatement(definition rewrite.Applicable) jen.Code {
+	if definition == nil {
+		return jen.Null()
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.BlockDefinition:
+		return jen.Block(g.renderBody(definition)...)
+	case rewrite.VariableDefinition:
+		if def.Assign != nil && def.Assign.Short && def.Type == nil && !def.Constant {
+			return jen.Id(g.names.Name(naming.Variable, def.Name)).Op(":=").Add(g.renderExpr(def.Assign.Value))
+		}
+		return g.renderVariableDeclaration(def)
+	case rewrite.AssignmentDefinition:
+		return g.renderAssignment(def)
+	case rewrite.IfDefinition:
+		return jen.If(g.renderCondition(def.Condition)).Block(g.renderBody(def.Body)...)
 	case rewrite.LoopDefinition:
+		if isEmptyCondition(def.Condition) {
+			return jen.For().Block(g.renderBody(def.Body)...)
+		}
+		return jen.For(g.renderCondition(def.Condition)).Block(g.renderBody(def.Body)...)
 	case rewrite.ForDefinition:
-	case rewrite.CaseDefinition:
+		return jen.For(
+			g.renderClause(def.Left),
+			g.renderClause(def.Middle),
+			g.renderClause(def.End),
+		).Block(g.renderBody(def.Body)...)
 	case rewrite.SwitchDefinition:
-	case rewrite.FieldDefinition:
-	case rewrite.FutureDefinition:
-	case rewrite.ChannelDefinition:
-	case rewrite.StreamDefinition:
+		return g.renderSwitch(def)
+	case rewrite.ReturnDefinition:
+		if def.Type == nil {
+			return jen.Return()
+		}
+		return jen.Return(g.renderExpr(def.Type))
+	case rewrite.MethodCallDefinition:
+		return g.renderCall(def)
+	case rewrite.ConditionDefinition:
+		return g.renderCondition(def)
+	case rewrite.CommentDefinition:
+		var statement = jen.Null()
+		for index, line := range commentLines(def.Contents...) {
+			if index != 0 {
+				statement.Line()
+			}
+			statement.Comment(line)
+		}
+		return statement
+	case rewrite.AnnotationDefinition:
+		return jen.Comment(Annotation(def))
+	}
+	return jen.Null()
+}
+
+// renderClause renders an init, condition or post clause of a for statement,
+// where a missing clause is left empty.
+func (g *golang) renderClause(definition rewrite.Applicable) jen.Code {
+	if definition == nil {
+		return jen.Empty()
+	}
+	if _, ok := definition.Elem().(rewrite.ConditionDefinition); ok {
+		return g.renderExpr(definition)
 	}
+	return g.renderStatement(definition)
 }
 
-func renderVariable(file *jen.File, variableDefinition rewrite.VariableDefinition) {
-	file.Var().Id(variableDefinition.GetName())
-	render(file, variableDefinition.Type)
-	if variableDefinition.Assign != nil {
-		render(file, variableDefinition.Assign)
+func (g *golang) renderSwitch(def rewrite.SwitchDefinition) *jen.Statement {
+	var statement = jen.Switch()
+	if !isEmptyCondition(def.Condition) {
+		statement = jen.Switch(g.renderCondition(def.Condition))
 	}
+
+	return statement.BlockFunc(func(cases *jen.Group) {
+		for _, item := range def.Cases {
+			if isEmptyCondition(item.Condition) {
+				cases.Default().Block(g.renderBody(item.Body)...)
+				continue
+			}
+			cases.Case(g.renderCondition(item.Condition)).Block(g.renderBody(item.Body)...)
+		}
+	})
 }
 
-func renderAssignment(file *jen.File, def rewrite.AssignmentDefinition) {
+func (g *golang) renderCall(def rewrite.MethodCallDefinition) *jen.Statement {
+	var name = def.Name
+	if name == "" && def.Method != nil {
+		name = def.Method.Name
+	}
+
+	var call = jen.Id(name).CallFunc(func(args *jen.Group) {
+		for _, arg := range def.Arguments {
+			if arg.Assign != nil && arg.Assign.Value != nil {
+				args.Add(g.renderExpr(arg.Assign.Value))
+				continue
+			}
+			args.Id(g.names.Name(naming.Variable, arg.Name))
+		}
+	})
 
+	if len(def.Results) == 0 {
+		return call
+	}
+
+	return jen.ListFunc(func(results *jen.Group) {
+		for _, result := range def.Results {
+			if result.Name == "" {
+				results.Id("_")
+				continue
+			}
+			results.Id(g.names.Name(naming.Variable, result.Name))
+		}
+	}).Op(":=").Add(call)
+}
+
+// renderExpr renders the definition as a Go expression.
+func (g *golang) renderExpr(definition rewrite.Applicable) *jen.Statement {
+	if definition == nil {
+		return jen.Nil()
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.Value:
+		if def.Value != nil {
+			return g.renderExpr(def.Value)
+		}
+		return jen.Id(def.Name)
+	case rewrite.ConditionDefinition:
+		return g.renderCondition(def)
+	case rewrite.MethodCallDefinition:
+		return g.renderCall(def)
+	case rewrite.AssignmentDefinition:
+		return g.renderAssignment(def)
+	case rewrite.DataDefinition:
+		return jen.Id(def.Name).Values()
+	}
+
+	if named, ok := definition.(interface{ GetName() string }); ok && named.GetName() != "" {
+		return jen.Id(g.names.Name(naming.Variable, named.GetName()))
+	}
+	return jen.Nil()
+}
+
+// renderCondition renders a condition, nested conditions are wrapped
+// in parentheses to retain their precedence.
+func (g *golang) renderCondition(def rewrite.ConditionDefinition) *jen.Statement {
+	var operator = def.Operator.Operator
+	if operator == 0 {
+		return g.renderOperand(def.Left)
+	}
+
+	var op, ok = operators[operator]
+	if !ok {
+		g.setErr(fmt.Errorf("go: unknown operator %d", operator))
+		return jen.Null()
+	}
+
+	switch operator {
+	case rewrite.Increment, rewrite.Decrement:
+		return g.renderOperand(def.Left).Op(op)
+	case rewrite.BitwiseNot:
+		if def.Left == nil {
+			return jen.Op(op).Add(g.renderOperand(def.Right))
+		}
+		return jen.Op(op).Add(g.renderOperand(def.Left))
+	}
+	return g.renderOperand(def.Left).Op(op).Add(g.renderOperand(def.Right))
+}
+
+// operators maps operators to their Go tokens, where the bitwise
+// complement is the unary form of ^.
+var operators = map[rewrite.Operator]string{
+	rewrite.Equal:              "=",
+	rewrite.NotEquality:        "!=",
+	rewrite.Equality:           "==",
+	rewrite.Increment:          "++",
+	rewrite.Decrement:          "--",
+	rewrite.Multiplication:     "*",
+	rewrite.Subtraction:        "-",
+	rewrite.Division:           "/",
+	rewrite.Addition:           "+",
+	rewrite.SelfMultiplication: "*=",
+	rewrite.SelfSubtraction:    "-=",
+	rewrite.SelfDivision:       "/=",
+	rewrite.SelfAddition:       "+=",
+	rewrite.Modulo:             "%",
+	rewrite.LessThan:           "<",
+	rewrite.GreaterThan:        ">",
+	rewrite.LessThanEqualTo:    "<=",
+	rewrite.GreaterThanEqualTo: ">=",
+	rewrite.ConditionalAnd:     "&&",
+	rewrite.ConditionalOR:      "||",
+	rewrite.BinaryAnd:          "&",
+	rewrite.BinaryOR:           "|",
+	rewrite.BitwiseNot:         "^",
+	rewrite.BitwiseAnd:         "&",
+	rewrite.BitwiseOR:          "|",
+	rewrite.BitwiseXOR:         "^",
+	rewrite.LeftShift:          "<<",
+	rewrite.RightShift:         ">>",
+}
+
+func (g *golang) renderOperand(definition rewrite.Applicable) *jen.Statement {
+	if definition == nil {
+		return jen.Nil()
+	}
+	if condition, ok := definition.Elem().(rewrite.ConditionDefinition); ok && condition.Right != nil {
+		return jen.Parens(g.renderCondition(condition))
+	}
+	return g.renderExpr(definition)
+}
+
+// namedReturns returns true if all returns are named, as Go requires
+// return values to be either all named or all unnamed.
+func namedReturns(returns []rewrite.ReturnDefinition) bool {
+	for _, ret := range returns {
+		if ret.Name == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func isEmptyCondition(def rewrite.ConditionDefinition) bool {
+	return def.Left == nil && def.Right == nil
+}
+
+// comment renders text as line comments, one per line of text, as jen
+// renders text of several lines as a block comment which */ would end.
+func comment(group *jen.Group, text string) {
+	for _, line := range commentLines(text) {
+		group.Comment(line)
+	}
+}
+
+// commentLines returns the lines of the non-empty texts.
+func commentLines(texts ...string) []string {
+	var lines []string
+	for _, text := range texts {
+		if text != "" {
+			lines = append(lines, strings.Split(text, "\n")...)
+		}
+	}
+	return lines
+}
+
+func receiverName(name string) string {
+	if name == "" {
+		return "_"
+	}
+	return strings.ToLower(name[:1])
 }
@@ -0,0 +1,270 @@
+package generators_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/typemap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var counter = &rewrite.VariableDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "count"}}
+	var age = &rewrite.VariableDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "age"}}
+
+	var user = rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "User is a registered user."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "Name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "Age"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "Joined"}, Type: &rewrite.TypeDefinition{Type: rewrite.Time}},
+		},
+		Methods: []rewrite.MethodDefinition{
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "IsAdult"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "age"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+				},
+				Returns: []rewrite.ReturnDefinition{
+					{Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+				Data: &rewrite.BlockDefinition{
+					Statements: []rewrite.Applicable{
+						&rewrite.VariableDefinition{
+							BaseDefinition: rewrite.BaseDefinition{Name: "count"},
+							Assign: &rewrite.AssignmentDefinition{
+								Short: true,
+								Value: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "0"}},
+							},
+						},
+						&rewrite.ForDefinition{
+							Left: &rewrite.AssignmentDefinition{
+								BaseDefinition: rewrite.BaseDefinition{Name: "i"},
+								Short:          true,
+								Value:          &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "0"}},
+							},
+							Middle: &rewrite.ConditionDefinition{
+								Left:     &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "i"}},
+								Right:    age,
+								Operator: rewrite.OperatorDefinition{Operator: rewrite.LessThan},
+							},
+							End: &rewrite.ConditionDefinition{
+								Left:     &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "i"}},
+								Operator: rewrite.OperatorDefinition{Operator: rewrite.Increment},
+							},
+							Body: &rewrite.ConditionDefinition{
+								Left:     counter,
+								Operator: rewrite.OperatorDefinition{Operator: rewrite.Increment},
+							},
+						},
+						&rewrite.SwitchDefinition{
+							Cases: []rewrite.CaseDefinition{
+								{
+									Condition: rewrite.ConditionDefinition{
+										Left:     counter,
+										Right:    &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "18"}},
+										Operator: rewrite.OperatorDefinition{Operator: rewrite.GreaterThanEqualTo},
+									},
+									Body: &rewrite.ReturnDefinition{Type: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: `"adult"`}}},
+								},
+							},
+						},
+						&rewrite.IfDefinition{
+							Condition: rewrite.ConditionDefinition{
+								Left:     counter,
+								Right:    &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "0"}},
+								Operator: rewrite.OperatorDefinition{Operator: rewrite.Equality},
+							},
+							Body: &rewrite.ReturnDefinition{Type: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: `"unborn"`}}},
+						},
+						&rewrite.ReturnDefinition{Type: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: `"minor"`}}},
+					},
+				},
+			},
+		},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models", Description: "Package models.", Version: "0.1"},
+		Definitions: []rewrite.Applicable{
+			&user,
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Updates"},
+				Returns: []rewrite.ReturnDefinition{
+					{Type: &rewrite.ChannelDefinition{Direction: rewrite.IncomingDirectional, Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "User"}}}},
+				},
+			},
+			&rewrite.VariableDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "limit"},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.Integer},
+			},
+			&rewrite.AssignmentDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "limit"},
+				Value:          &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "10"}},
+			},
+		},
+	}
+
+	var file, err = generators.Render(pkg)
+	require.NoError(t, err)
+	var code = file.GoString()
+	require.True(t, strings.HasPrefix(code, "// Package models.\n// Version: 0.1\npackage models\n"))
+	require.Contains(t, code, "// User is a registered user.\ntype User struct {\n\tName   string\n\tAge    int32\n\tJoined time.Time\n}")
+	require.Contains(t, code, "func (u *User) IsAdult(age int64) string {")
+	require.Contains(t, code, "count := 0")
+	require.Contains(t, code, "for i := 0; i < age; i++ {\n\t\tcount++\n\t}")
+	require.Contains(t, code, "switch {\n\tcase count >= 18:\n\t\treturn \"adult\"\n\t}")
+	require.Contains(t, code, "if count == 0 {\n\t\treturn \"unborn\"\n\t}")
+	require.Contains(t, code, "func Updates() <-chan User {\n\t// TODO: implement Updates.\n\treturn *new(<-chan User)\n}")
+	require.Contains(t, code, "var limit int64")
+	require.Contains(t, code, "func init() {\n\tlimit = 10\n}")
+}
+
+func TestRenderTypeOverride(t *testing.T) {
+	typemap.Override("go", typemap.Key{Type: rewrite.Decimal}, typemap.Type{Name: "Decimal", Import: "github.com/shopspring/decimal"})
+	defer typemap.Reset("go")
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "billing"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Invoice"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "Total"}, Type: &rewrite.TypeDefinition{Type: rewrite.Decimal}},
+				},
+			},
+		},
+	}
+
+	var file, err = generators.Render(pkg)
+	require.NoError(t, err)
+	var code = file.GoString()
+	require.Contains(t, code, `"github.com/shopspring/decimal"`)
+	require.Contains(t, code, "Total decimal.Decimal")
+}
+
+func TestRenderNaming(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "User"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "user_id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "Name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+			},
+		},
+	}
+
+	var file, err = generators.Render(pkg)
+	require.NoError(t, err)
+	var code = file.GoString()
+	require.Contains(t, code, "UserID int64 `json:\"user_id\"`")
+	require.Contains(t, code, "Name   string\n")
+}
+
+func TestRenderKeywords(t *testing.T) {
+	var typ = &rewrite.VariableDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "type"}}
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "search"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Find"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "type"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+				Returns: []rewrite.ReturnDefinition{{Type: &rewrite.TypeDefinition{Type: rewrite.String}}},
+				Data: &rewrite.BlockDefinition{
+					Statements: []rewrite.Applicable{
+						&rewrite.ReturnDefinition{Type: typ},
+					},
+				},
+			},
+			&rewrite.VariableDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "var"},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+			},
+			&rewrite.AssignmentDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "var"},
+				Value:          &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: `"any"`}},
+			},
+		},
+	}
+
+	var file, err = generators.Render(pkg)
+	require.NoError(t, err)
+	var code = file.GoString()
+	require.Contains(t, code, "func Find(type_ string) string {\n\treturn type_\n}")
+	require.Contains(t, code, "var var_ string")
+	require.Contains(t, code, "var_ = \"any\"")
+}
+
+func TestRenderOperators(t *testing.T) {
+	var mask = &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "mask"}}
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "flags"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Invert"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "mask"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+				},
+				Returns: []rewrite.ReturnDefinition{{Type: &rewrite.TypeDefinition{Type: rewrite.Integer}}},
+				Data: &rewrite.ReturnDefinition{Type: &rewrite.ConditionDefinition{
+					Left:     mask,
+					Operator: rewrite.OperatorDefinition{Operator: rewrite.BitwiseNot},
+				}},
+			},
+		},
+	}
+
+	var file, err = generators.Render(pkg)
+	require.NoError(t, err)
+	require.Contains(t, file.GoString(), "return ^mask")
+
+	pkg.Definitions = []rewrite.Applicable{
+		&rewrite.AssignmentDefinition{
+			BaseDefinition: rewrite.BaseDefinition{Name: "mask"},
+			Value: &rewrite.ConditionDefinition{
+				Left:     mask,
+				Right:    mask,
+				Operator: rewrite.OperatorDefinition{Operator: rewrite.Operator(99)},
+			},
+		},
+	}
+	_, err = generators.Render(pkg)
+	require.Error(t, err)
+}
+
+func TestRenderUnnamedType(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.TypeDefinition{Type: rewrite.String},
+		},
+	}
+
+	var _, err = generators.Render(pkg)
+	require.Error(t, err)
+}
+
+func TestRenderComments(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "paths", Description: "Package paths matches paths.\nIt supports */ globs."},
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataTypeDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Glob", Description: "Glob is a pattern such as */*.go.\nIt is never empty."},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+			},
+		},
+	}
+
+	var file, err = generators.Render(pkg)
+	require.NoError(t, err)
+	var code = file.GoString()
+	require.Contains(t, code, "// Package paths matches paths.\n// It supports */ globs.\npackage paths")
+	require.Contains(t, code, "// Glob is a pattern such as */*.go.\n// It is never empty.\ntype Glob string")
+}
@@ -0,0 +1,235 @@
+// Package golden tests generators against golden files, the expected output
+// of a generator checked in next to the test which renders it.
+//
+// Tests describe a package with stackexpr, run a registered generator over
+// it and compare every produced file with its golden file, reporting a
+// line diff of any mismatch. Run writes the produced files as the new
+// golden files instead when updating, which tests usually tie to a flag of
+// their own:
+//
+//	var update = flag.Bool("update", false, "update golden files")
+//
+//	func TestGenerators(t *testing.T) {
+//		golden.Run(t, "go", pkg, "testdata/go", *update)
+//	}
+package golden
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/stackexpr"
+)
+
+// Extension is appended to the name of a generated file to name its
+// golden file.
+const Extension = ".golden"
+
+// Build returns the package definition described by fn, which describes
+// the package with the stackexpr functions, e.g:
+//
+//	golden.Build(func(target *stackexpr.Description) {
+//		stackexpr.UseName(target, "models")
+//		stackexpr.UseData(target, func() {
+//			stackexpr.UseName(target, "User")
+//		})
+//	})
+func Build(fn func(target *stackexpr.Description)) (rewrite.PackageDefinition, error) {
+	var pkg rewrite.PackageDefinition
+	var _, err = stackexpr.Describe(func(stack rewrite.Stack) {
+		fn(stack.(*stackexpr.Description))
+	})(&pkg)
+	if err != nil {
+		return pkg, fmt.Errorf("golden: %w", err)
+	}
+	return pkg, nil
+}
+
+// Run generates pkg with the generator registered as name and compares the
+// produced files with the golden files within dir, where the golden file of
+// "models.go" is "models.go.golden". Golden files which are no longer
+// produced are reported. If update is true, the golden files are written
+// with the produced files instead and those no longer produced removed.
+func Run(t testing.TB, name string, pkg rewrite.PackageDefinition, dir string, update bool) {
+	t.Helper()
+
+	var generator, err = generators.Lookup(name)
+	if err != nil {
+		t.Fatalf("golden: %s", err)
+	}
+
+	files, err := generator.Generate(pkg)
+	if err != nil {
+		t.Fatalf("golden: %s: %s", name, err)
+	}
+
+	existing, err := goldenFiles(dir)
+	if err != nil {
+		t.Fatalf("golden: %s", err)
+	}
+
+	var produced = make(map[string]bool, len(files))
+	for _, file := range files {
+		var path = filepath.Join(dir, filepath.FromSlash(file.Name)+Extension)
+		produced[path] = true
+
+		if update {
+			if err := write(path, file.Content); err != nil {
+				t.Fatalf("golden: %s", err)
+			}
+			continue
+		}
+
+		want, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			t.Errorf("golden: %s: no golden file for %s, run the tests in update mode to create it", name, path)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("golden: %s", err)
+		}
+
+		if !bytes.Equal(want, file.Content) {
+			t.Errorf("golden: %s: %s does not match its golden file, update the golden files if the change is intended:\n%s",
+				name, file.Name, Diff(string(want), string(file.Content)))
+		}
+	}
+
+	for _, path := range existing {
+		if produced[path] {
+			continue
+		}
+		if update {
+			if err := os.Remove(path); err != nil {
+				t.Fatalf("golden: %s", err)
+			}
+			continue
+		}
+		t.Errorf("golden: %s: %s is no longer generated, update the golden files to remove it", name, path)
+	}
+}
+
+// goldenFiles returns the paths of the golden files within dir.
+func goldenFiles(dir string) ([]string, error) {
+	var paths []string
+	var err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(path, Extension) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return paths, err
+}
+
+func write(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+// context is the number of unchanged lines shown around changed lines.
+const context = 3
+
+// Diff returns a line diff from want to got, where removed lines are
+// prefixed with "-", added lines with "+" and unchanged lines with a
+// space. Unchanged lines away from changes are elided, and the diff of
+// equal texts is empty.
+func Diff(want string, got string) string {
+	if want == got {
+		return ""
+	}
+
+	var lines = diff(strings.Split(want, "\n"), strings.Split(got, "\n"))
+
+	// keep unchanged lines within context of a changed line.
+	var keep = make([]bool, len(lines))
+	for index, line := range lines {
+		if line.op == ' ' {
+			continue
+		}
+		for near := index - context; near <= index+context; near++ {
+			if near >= 0 && near < len(lines) {
+				keep[near] = true
+			}
+		}
+	}
+
+	var out strings.Builder
+	var elided bool
+	for index, line := range lines {
+		if !keep[index] {
+			if !elided {
+				out.WriteString("   ...\n")
+				elided = true
+			}
+			continue
+		}
+		elided = false
+		fmt.Fprintf(&out, "%c %4d %s\n", line.op, line.number, line.text)
+	}
+	return out.String()
+}
+
+type line struct {
+	op     byte
+	number int
+	text   string
+}
+
+// diff returns the edit script from want to got by their longest common
+// subsequence of lines, numbering lines by their position in got, or in
+// want for removed lines.
+func diff(want []string, got []string) []line {
+	var common = make([][]int, len(want)+1)
+	for index := range common {
+		common[index] = make([]int, len(got)+1)
+	}
+	for i := len(want) - 1; i >= 0; i-- {
+		for j := len(got) - 1; j >= 0; j-- {
+			if want[i] == got[j] {
+				common[i][j] = common[i+1][j+1] + 1
+				continue
+			}
+			common[i][j] = max(common[i+1][j], common[i][j+1])
+		}
+	}
+
+	var lines []line
+	var i, j int
+	for i < len(want) || j < len(got) {
+		switch {
+		case i < len(want) && j < len(got) && want[i] == got[j]:
+			lines = append(lines, line{op: ' ', number: j + 1, text: got[j]})
+			i++
+			j++
+		case i < len(want) && (j == len(got) || common[i+1][j] >= common[i][j+1]):
+			lines = append(lines, line{op: '-', number: i + 1, text: want[i]})
+			i++
+		default:
+			lines = append(lines, line{op: '+', number: j + 1, text: got[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,201 @@
+package golden_test
+
+import (
+	"flag"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	_ "github.com/influx6/rewrite/generators/avro"
+	_ "github.com/influx6/rewrite/generators/c"
+	_ "github.com/influx6/rewrite/generators/csharp"
+	_ "github.com/influx6/rewrite/generators/dart"
+	_ "github.com/influx6/rewrite/generators/diagram"
+	_ "github.com/influx6/rewrite/generators/docs"
+	"github.com/influx6/rewrite/generators/golden"
+	"github.com/influx6/rewrite/generators/graphql"
+	_ "github.com/influx6/rewrite/generators/java"
+	_ "github.com/influx6/rewrite/generators/javascript"
+	_ "github.com/influx6/rewrite/generators/jsonschema"
+	_ "github.com/influx6/rewrite/generators/kotlin"
+	"github.com/influx6/rewrite/generators/openapi"
+	"github.com/influx6/rewrite/generators/protobuf"
+	_ "github.com/influx6/rewrite/generators/python"
+	_ "github.com/influx6/rewrite/generators/rust"
+	_ "github.com/influx6/rewrite/generators/sql"
+	_ "github.com/influx6/rewrite/generators/swift"
+	"github.com/influx6/rewrite/generators/thrift"
+	_ "github.com/influx6/rewrite/generators/typescript"
+	. "github.com/influx6/rewrite/stackexpr"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// pin pins the wire number of a field for the backends which require one.
+func pin(target *Description, number string) {
+	UseAnnotation(target, number, func() {
+		UseName(target, protobuf.FieldNumberAnnotation)
+	})
+	UseAnnotation(target, number, func() {
+		UseName(target, thrift.FieldIDAnnotation)
+	})
+}
+
+func billing(target *Description) {
+	UseName(target, "billing")
+	UseDescription(target, "Billing holds the accounts and invoices of users.")
+	UseVersion(target, "1.0.0")
+
+	UseDataType(target, func() {
+		UseName(target, "email_address")
+		UseDescription(target, "Email is the address invoices are sent to.")
+		UseType(target, func() {
+			UseBaseType(target, rewrite.String)
+		})
+	})
+
+	UseData(target, func() {
+		UseName(target, "Invoice")
+		UseDescription(target, "Invoice is a payment requested from an account.")
+		UseField(target, func() {
+			UseName(target, "invoice_id")
+			pin(target, "1")
+			UseType(target, func() {
+				UseBaseType(target, rewrite.Integer)
+			})
+		})
+		UseField(target, func() {
+			UseName(target, "amount")
+			UseDescription(target, "Amount is the total in cents.")
+			pin(target, "2")
+			UseType(target, func() {
+				UseBaseType(target, rewrite.Integer)
+				UseMemory(target, rewrite.Bit32)
+			})
+		})
+		UseField(target, func() {
+			UseName(target, "issued_at")
+			pin(target, "3")
+			UseType(target, func() {
+				UseBaseType(target, rewrite.Time)
+			})
+		})
+		UseField(target, func() {
+			UseName(target, "billing_email")
+			pin(target, "4")
+			UseDataType(target, func() {
+				UseName(target, "email_address")
+				UseType(target, func() {
+					UseBaseType(target, rewrite.String)
+				})
+			})
+		})
+		UseField(target, func() {
+			UseName(target, "currency")
+			pin(target, "5")
+			UseType(target, func() {
+				UseBaseType(target, rewrite.String)
+			})
+		})
+	})
+
+	UseData(target, func() {
+		UseName(target, "InvoiceQuery")
+		UseAnnotation(target, "", func() {
+			UseName(target, graphql.InputAnnotation)
+		})
+		UseField(target, func() {
+			UseName(target, "invoice_id")
+			pin(target, "1")
+			UseType(target, func() {
+				UseBaseType(target, rewrite.Integer)
+			})
+		})
+	})
+
+	UseData(target, func() {
+		UseName(target, "Invoices")
+		UseDescription(target, "Invoices looks up the invoices of an account.")
+		UseAnnotation(target, "", func() {
+			UseName(target, protobuf.ServiceAnnotation)
+		})
+		UseMethod(target, func() {
+			UseName(target, "find_invoice")
+			UseDescription(target, "FindInvoice returns the invoice matching the query.")
+			UseAnnotation(target, "POST /invoices/search", func() {
+				UseName(target, openapi.OperationAnnotation)
+			})
+			UseField(target, func() {
+				UseName(target, "query")
+				pin(target, "1")
+				UseDataType(target, func() {
+					UseName(target, "InvoiceQuery")
+				})
+			})
+			UseReturn(target, func() {
+				UseDataType(target, func() {
+					UseName(target, "Invoice")
+				})
+			})
+		})
+	})
+}
+
+func TestGenerators(t *testing.T) {
+	var pkg, err = golden.Build(billing)
+	require.NoError(t, err)
+
+	for _, name := range generators.Generators.Names() {
+		var name = name
+		t.Run(name, func(t *testing.T) {
+			golden.Run(t, name, pkg, filepath.Join("testdata", filepath.FromSlash(name)), *update)
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	var pkg, err = golden.Build(billing)
+	require.NoError(t, err)
+	require.Equal(t, "billing", pkg.Name)
+	require.Len(t, pkg.Definitions, 4)
+
+	var invoice, ok = pkg.Definitions[1].Elem().(rewrite.DataDefinition)
+	require.True(t, ok)
+	require.Len(t, invoice.Fields, 5)
+	require.Equal(t, "amount", invoice.Fields[1].Name)
+	require.Equal(t, rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32}, invoice.Fields[1].Type.Elem())
+	require.True(t, invoice.Fields[1].Annotations.Has(protobuf.FieldNumberAnnotation))
+
+	var invoices = pkg.Definitions[3].Elem().(rewrite.DataDefinition)
+	require.Len(t, invoices.Methods, 1)
+	require.Len(t, invoices.Methods[0].Arguments, 1)
+	require.Len(t, invoices.Methods[0].Returns, 1)
+
+	_, err = golden.Build(func(target *Description) {
+		UseData(target, func() {
+			UseBaseType(target, rewrite.String)
+		})
+	})
+	require.Error(t, err)
+}
+
+func TestDiff(t *testing.T) {
+	var want = "a\nb\nc\nd\ne\nf\ng\nh\ni\n"
+	var got = "a\nb\nc\nd\ne\nf\ng\nH\ni\n"
+	require.Equal(t, strings.Join([]string{
+		"   ...",
+		"     5 e",
+		"     6 f",
+		"     7 g",
+		"-    8 h",
+		"+    8 H",
+		"     9 i",
+		"    10 ",
+		"",
+	}, "\n"), golden.Diff(want, got))
+
+	require.Equal(t, "", golden.Diff(want, want))
+}
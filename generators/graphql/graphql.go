@@ -0,0 +1,303 @@
+// Package graphql renders a rewrite.PackageDefinition into a GraphQL
+// schema in the schema definition language (SDL).
+package graphql
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Annotations understood by the graphql backend.
+const (
+	// MutationAnnotation marks a package method as a Mutation field.
+	MutationAnnotation = "mutation"
+
+	// SubscriptionAnnotation marks a package method as a Subscription field,
+	// methods returning a StreamDefinition are subscriptions by default.
+	SubscriptionAnnotation = "subscription"
+
+	// NullableAnnotation marks a field or method result as nullable,
+	// all other fields are rendered as non-null.
+	NullableAnnotation = "nullable"
+
+	// InputAnnotation marks a DataDefinition as an input type.
+	InputAnnotation = "input"
+)
+
+// blockString escapes the closing quotes of block string descriptions.
+var blockString = strings.NewReplacer(`"""`, `\"""`)
+
+func init() {
+	typemap.Defaults("graphql", typemap.Table{
+		{Type: rewrite.String}:                         {Name: "String"},
+		{Type: rewrite.Rune}:                           {Name: "String"},
+		{Type: rewrite.Integer}:                        {Name: "Int64"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "Int"},
+		{Type: rewrite.Decimal}:                        {Name: "Float"},
+		{Type: rewrite.Complex}:                        {Name: "Complex"},
+		{Type: rewrite.Time}:                           {Name: "Time"},
+	})
+	generators.Register(generators.Single("graphql", ".graphql", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg)
+	}))
+}
+
+// Render returns the GraphQL schema for the giving package definition.
+//
+// DataDefinition are rendered as types, with their methods as fields
+// taking arguments. Methods declared on the package are grouped into the
+// Query, Mutation and Subscription root types, where Query is the default
+// unless the method is annotated or returns a StreamDefinition.
+//
+// GraphQL defines Int as a signed 32-bit integer, so only 32-bit integers
+// are rendered as Int while others are declared as the custom scalar
+// Int64. Named types are declared as custom scalars, which requires a
+// valid GraphQL name.
+//
+// A field resolves to exactly one value, so methods without a return or
+// with several returns are reported as errors, as are methods of input
+// types, whose fields can not take arguments.
+func Render(pkg rewrite.PackageDefinition) ([]byte, error) {
+	var r = renderer{
+		Renderer: generators.Renderer{
+			DocFormat: `"""%s"""`,
+			DocBlock:  [3]string{`"""`, "", `"""`},
+			DocEscape: blockString.Replace,
+		},
+		scalars:  map[string]bool{},
+		declared: map[string]bool{},
+		types:    typemap.For("graphql"),
+	}
+
+	var roots = map[string][]rewrite.MethodDefinition{}
+	for _, definition := range pkg.Definitions {
+		if definition == nil {
+			continue
+		}
+		if method, ok := definition.Elem().(rewrite.MethodDefinition); ok {
+			var root = rootType(method)
+			roots[root] = append(roots[root], method)
+			continue
+		}
+		r.render(definition)
+	}
+
+	for _, root := range []string{"Query", "Mutation", "Subscription"} {
+		if methods, ok := roots[root]; ok {
+			r.renderRoot(root, methods)
+		}
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	var out bytes.Buffer
+	if pkg.Description != "" {
+		fmt.Fprintf(&out, "%s\n", generators.Comment("# %s", pkg.Description))
+	}
+	if pkg.Version != "" {
+		fmt.Fprintf(&out, "# Version: %s\n", pkg.Version)
+	}
+
+	var scalars = make([]string, 0, len(r.scalars))
+	for scalar := range r.scalars {
+		if !r.declared[scalar] {
+			scalars = append(scalars, scalar)
+		}
+	}
+	sort.Strings(scalars)
+	for _, scalar := range scalars {
+		fmt.Fprintf(&out, "\nscalar %s\n", scalar)
+	}
+
+	out.Write(r.Out.Bytes())
+	return out.Bytes(), nil
+}
+
+// rootType returns the root type a package method belongs to.
+func rootType(method rewrite.MethodDefinition) string {
+	switch {
+	case method.Annotations.Has(MutationAnnotation):
+		return "Mutation"
+	case method.Annotations.Has(SubscriptionAnnotation):
+		return "Subscription"
+	}
+	for _, ret := range method.Returns {
+		if ret.Type == nil {
+			continue
+		}
+		if _, ok := ret.Type.Elem().(rewrite.StreamDefinition); ok {
+			return "Subscription"
+		}
+	}
+	return "Query"
+}
+
+type renderer struct {
+	generators.Renderer
+	types typemap.Table
+
+	// scalars holds the custom scalars base types map to, which are
+	// declared unless the package declares them itself.
+	scalars  map[string]bool
+	declared map[string]bool
+}
+
+func (r *renderer) render(definition rewrite.Applicable) {
+	switch def := definition.Elem().(type) {
+	case rewrite.CommentDefinition:
+		r.Printf("\n")
+		for _, line := range def.Contents {
+			r.Printf("# %s\n", line)
+		}
+	case rewrite.DataDefinition:
+		r.renderData(def)
+	case rewrite.DataTypeDefinition:
+		r.renderScalar(def.Name, def.Description)
+	case rewrite.TypeDefinition:
+		r.renderScalar(def.Name, def.Description)
+	}
+}
+
+// renderScalar declares a named type as a custom scalar.
+func (r *renderer) renderScalar(name string, description string) {
+	if !isName(name) {
+		r.SetErr(fmt.Errorf("graphql: scalar %q requires a name of letters, digits and underscores", name))
+		return
+	}
+	if r.declared[name] {
+		r.SetErr(fmt.Errorf("graphql: scalar %q is declared twice", name))
+		return
+	}
+	r.declared[name] = true
+	r.Doc("", description)
+	r.Printf("scalar %s\n", name)
+}
+
+func (r *renderer) renderData(def rewrite.DataDefinition) {
+	var keyword = "type"
+	if def.Annotations.Has(InputAnnotation) {
+		keyword = "input"
+		if len(def.Methods) != 0 {
+			r.SetErr(fmt.Errorf("graphql: input %q can not declare method %q, input fields take no arguments", def.Name, def.Methods[0].Name))
+			return
+		}
+	}
+
+	r.Doc("", def.Description)
+	r.Printf("%s %s {\n", keyword, def.Name)
+	for _, field := range def.Fields {
+		r.Doc("\t", field.Description)
+		r.Printf("\t%s: %s\n", field.Name, r.fieldType(field.Type, field.Annotations))
+	}
+	for _, method := range def.Methods {
+		r.renderField(def.Name, method)
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderRoot(name string, methods []rewrite.MethodDefinition) {
+	r.Printf("\ntype %s {\n", name)
+	for _, method := range methods {
+		r.renderField(name, method)
+	}
+	r.Printf("}\n")
+}
+
+// renderField renders a method of the type named owner as a field with
+// arguments.
+func (r *renderer) renderField(owner string, method rewrite.MethodDefinition) {
+	if len(method.Returns) != 1 {
+		r.SetErr(fmt.Errorf("graphql: method %q of %q has %d returns, a field must resolve to a single return", method.Name, owner, len(method.Returns)))
+		return
+	}
+
+	r.Doc("\t", method.Description)
+	r.Printf("\t%s", method.Name)
+	if len(method.Arguments) != 0 {
+		var args = make([]string, 0, len(method.Arguments))
+		for _, arg := range method.Arguments {
+			args = append(args, fmt.Sprintf("%s: %s", arg.Name, r.fieldType(arg.Type, arg.Annotations)))
+		}
+		r.Printf("(%s)", strings.Join(args, ", "))
+	}
+	r.Printf(": %s\n", r.fieldType(method.Returns[0].Type, method.Annotations))
+}
+
+// fieldType returns the GraphQL type of a field, which is non-null
+// unless annotated as nullable.
+func (r *renderer) fieldType(definition rewrite.Applicable, annotations rewrite.Annotations) string {
+	var name = r.typeName(definition)
+	if annotations.Has(NullableAnnotation) {
+		return name
+	}
+	return name + "!"
+}
+
+// typeName returns the GraphQL type for the giving type definition,
+// futures and streams resolve to the type of the value they deliver.
+func (r *renderer) typeName(definition rewrite.Applicable) string {
+	if definition == nil {
+		r.SetErr(fmt.Errorf("graphql: missing type definition"))
+		return ""
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return r.baseType(def)
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return r.typeName(def.Type)
+		}
+		return def.Name
+	case rewrite.DataDefinition:
+		return def.Name
+	case rewrite.FutureDefinition:
+		return r.typeName(def.Type)
+	case rewrite.StreamDefinition:
+		return r.typeName(def.Type)
+	case rewrite.FieldDefinition:
+		return r.typeName(def.Type)
+	case rewrite.ReturnDefinition:
+		return r.typeName(def.Type)
+	}
+
+	r.SetErr(fmt.Errorf("graphql: %T can not be expressed as a type", definition.Elem()))
+	return ""
+}
+
+// baseType returns the GraphQL type of a base type, declaring it as a
+// custom scalar when it is not one of the built in scalars.
+func (r *renderer) baseType(def rewrite.TypeDefinition) string {
+	var typ, ok = r.types.Lookup(def)
+	if !ok {
+		r.SetErr(fmt.Errorf("graphql: unknown base type %q", def.Type))
+		return ""
+	}
+	switch typ.Name {
+	case "String", "Int", "Float", "Boolean", "ID":
+	default:
+		r.scalars[typ.Name] = true
+	}
+	return typ.Name
+}
+
+// isName returns true if name is a valid GraphQL name.
+func isName(name string) bool {
+	for index, char := range name {
+		switch {
+		case char == '_', char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z':
+		case index != 0 && char >= '0' && char <= '9':
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
@@ -0,0 +1,191 @@
+package graphql_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/graphql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "A registered user."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "joined", Description: "When the user signed up."},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.Time},
+				Annotations:    rewrite.Annotations{{BaseDefinition: rewrite.BaseDefinition{Name: graphql.NullableAnnotation}}},
+			},
+		},
+	}
+
+	var id = rewrite.FieldDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.CommentDefinition{Contents: []string{"Users"}},
+			user,
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "user"},
+				Arguments:      []rewrite.FieldDefinition{id},
+				Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: user}}},
+			},
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "deleteUser"},
+				Arguments:      []rewrite.FieldDefinition{id},
+				Returns:        []rewrite.ReturnDefinition{{Type: user}},
+				Annotations:    rewrite.Annotations{{BaseDefinition: rewrite.BaseDefinition{Name: graphql.MutationAnnotation}}},
+			},
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "users"},
+				Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.StreamDefinition{Type: user}}},
+			},
+		},
+	}
+
+	var schema, err = graphql.Render(pkg)
+	require.NoError(t, err)
+	require.Equal(t, `
+scalar Int64
+
+scalar Time
+
+# Users
+
+"""A registered user."""
+type User {
+	name: String!
+	"""When the user signed up."""
+	joined: Time
+}
+
+type Query {
+	user(id: Int64!): User!
+}
+
+type Mutation {
+	deleteUser(id: Int64!): User!
+}
+
+type Subscription {
+	users: User!
+}
+`, string(schema))
+}
+
+func TestRenderMultipleReturns(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "user"},
+				Returns: []rewrite.ReturnDefinition{
+					{Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+					{Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+			},
+		},
+	}
+
+	var _, err = graphql.Render(pkg)
+	require.Error(t, err)
+}
+
+func TestRenderInvalidMethods(t *testing.T) {
+	var query = rewrite.FieldDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "query"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}}
+
+	var _, err = graphql.Render(rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "User"},
+				Methods:        []rewrite.MethodDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "save"}}},
+			},
+		},
+	})
+	require.EqualError(t, err, `graphql: method "save" of "User" has 0 returns, a field must resolve to a single return`)
+
+	_, err = graphql.Render(rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Filter"},
+				Annotations:    rewrite.Annotations{{BaseDefinition: rewrite.BaseDefinition{Name: graphql.InputAnnotation}}},
+				Fields:         []rewrite.FieldDefinition{query},
+				Methods: []rewrite.MethodDefinition{
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "matches"},
+						Arguments:      []rewrite.FieldDefinition{query},
+						Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.TypeDefinition{Type: rewrite.String}}},
+					},
+				},
+			},
+		},
+	})
+	require.EqualError(t, err, `graphql: input "Filter" can not declare method "matches", input fields take no arguments`)
+}
+
+func TestRenderScalars(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Invoice"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "lines"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32}},
+				},
+			},
+		},
+	}
+
+	var schema, err = graphql.Render(pkg)
+	require.NoError(t, err)
+	require.Equal(t, `
+scalar Int64
+
+type Invoice {
+	id: Int64!
+	lines: Int!
+}
+`, string(schema))
+
+	// a declared Int64 scalar is not declared again.
+	pkg.Definitions = append(pkg.Definitions, &rewrite.TypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "Int64", Description: "Int64 is a signed 64-bit integer."}, Type: rewrite.Integer})
+	schema, err = graphql.Render(pkg)
+	require.NoError(t, err)
+	require.Equal(t, `
+type Invoice {
+	id: Int64!
+	lines: Int!
+}
+
+"""Int64 is a signed 64-bit integer."""
+scalar Int64
+`, string(schema))
+
+	for _, name := range []string{"", "user-id"} {
+		_, err = graphql.Render(rewrite.PackageDefinition{
+			Definitions: []rewrite.Applicable{
+				&rewrite.TypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: name}, Type: rewrite.String},
+			},
+		})
+		require.Error(t, err, name)
+	}
+}
+
+func TestRenderDescriptions(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Quote", Description: `Quote holds text such as """.` + "\nIt is never empty."},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "text"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+			},
+		},
+	}
+
+	var code, err = graphql.Render(pkg)
+	require.NoError(t, err)
+	require.Contains(t, string(code), "\"\"\"\nQuote holds text such as \\\"\"\".\nIt is never empty.\n\"\"\"\ntype Quote {\n")
+}
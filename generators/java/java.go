@@ -0,0 +1,319 @@
+// Package java renders a rewrite.PackageDefinition into Java records and
+// interfaces for JVM consumers.
+package java
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/naming"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Options configures how definitions are rendered.
+type Options struct {
+	// Package sets the Java package, defaults to the package definition name.
+	Package string
+}
+
+func init() {
+	typemap.Defaults("java", typemap.Table{
+		{Type: rewrite.String}:                         {Name: "String"},
+		{Type: rewrite.Rune}:                           {Name: "char"},
+		{Type: rewrite.Integer}:                        {Name: "long"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "int"},
+		{Type: rewrite.Decimal}:                        {Name: "double"},
+		{Type: rewrite.Decimal, Memory: rewrite.Bit32}: {Name: "float"},
+		{Type: rewrite.Time}:                           {Name: "Instant", Import: "java.time.Instant"},
+	})
+	naming.Defaults("java", naming.Convention{
+		Cases: map[naming.Construct]naming.Case{
+			naming.Type:     naming.Pascal,
+			naming.Field:    naming.Camel,
+			naming.Method:   naming.Camel,
+			naming.Constant: naming.Screaming,
+			naming.Variable: naming.Camel,
+		},
+		Keywords: naming.Set(
+			"abstract", "assert", "boolean", "break", "byte", "case", "catch",
+			"char", "class", "const", "continue", "default", "do", "double",
+			"else", "enum", "extends", "false", "final", "finally", "float",
+			"for", "goto", "if", "implements", "import", "instanceof", "int",
+			"interface", "long", "native", "new", "null", "package", "private",
+			"protected", "public", "return", "short", "static", "strictfp",
+			"super", "switch", "synchronized", "this", "throw", "throws",
+			"transient", "true", "try", "void", "volatile", "while",
+		),
+	})
+	generators.Register(generators.Multiple("java", []string{".java"}, func(pkg rewrite.PackageDefinition) (map[string][]byte, error) {
+		return Render(pkg, Options{})
+	}))
+}
+
+// Render returns the Java source files for the giving package definition,
+// keyed by file name, as Java requires a file per public type.
+//
+// DataDefinition with fields are rendered as records, while those with only
+// methods are rendered as interfaces. Methods and variables declared on the
+// package are collected into an interface named after the package.
+// FutureDefinition are rendered as CompletableFuture and StreamDefinition as
+// Flow.Publisher. Statements found directly within the package are ignored.
+func Render(pkg rewrite.PackageDefinition, options Options) (map[string][]byte, error) {
+	var name = options.Package
+	if name == "" {
+		name = pkg.Name
+	}
+
+	var r = renderer{pkg: pkg, name: name, files: map[string][]byte{}, types: typemap.For("java"), names: naming.For("java")}
+
+	var methods []rewrite.MethodDefinition
+	var variables []rewrite.VariableDefinition
+	for _, definition := range pkg.Definitions {
+		if definition == nil {
+			continue
+		}
+
+		switch def := definition.Elem().(type) {
+		case rewrite.DataDefinition:
+			var f = r.file()
+			var name = r.names.Name(naming.Type, def.Name)
+			if len(def.Fields) == 0 {
+				f.renderInterface(name, def.Description, def.Methods, nil)
+			} else {
+				f.renderRecord(def)
+			}
+			r.write(name, f)
+		case rewrite.MethodDefinition:
+			methods = append(methods, def)
+		case rewrite.VariableDefinition:
+			variables = append(variables, def)
+		}
+	}
+
+	if len(methods) != 0 || len(variables) != 0 {
+		var f = r.file()
+		var title = "Package"
+		if pkg.Name != "" {
+			title = r.names.Name(naming.Type, pkg.Name)
+		}
+		f.renderInterface(title, "", methods, variables)
+		r.write(title, f)
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return r.files, nil
+}
+
+type renderer struct {
+	generators.Renderer
+	types typemap.Table
+	names naming.Convention
+	pkg   rewrite.PackageDefinition
+	name  string
+	files map[string][]byte
+}
+
+func (r *renderer) file() *file {
+	return &file{
+		renderer: r,
+		Renderer: generators.Renderer{
+			DocFormat: "/** %s */",
+			DocBlock:  [3]string{"/**", " * ", " */"},
+			DocEscape: generators.EscapeCommentEnd,
+		},
+		imports: map[string]bool{},
+	}
+}
+
+// write assembles the header, imports and body of a file, it reports an
+// error if another type was already written to the same file.
+func (r *renderer) write(typeName string, f *file) {
+	var path = typeName + ".java"
+	if _, ok := r.files[path]; ok {
+		r.SetErr(fmt.Errorf("java: several definitions render to the class %q in %s", typeName, path))
+		return
+	}
+
+	var out bytes.Buffer
+	if r.pkg.Description != "" {
+		fmt.Fprintf(&out, "%s\n", generators.Comment("// %s", r.pkg.Description))
+	}
+	if r.pkg.Version != "" {
+		fmt.Fprintf(&out, "// Version: %s\n", r.pkg.Version)
+	}
+	if r.name != "" {
+		fmt.Fprintf(&out, "package %s;\n", r.name)
+	}
+
+	var imports = make([]string, 0, len(f.imports))
+	for path := range f.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	if len(imports) != 0 {
+		out.WriteString("\n")
+	}
+	for _, path := range imports {
+		fmt.Fprintf(&out, "import %s;\n", path)
+	}
+
+	r.SetErr(f.Err)
+	out.Write(f.Out.Bytes())
+	r.files[path] = out.Bytes()
+}
+
+// file renders the public type of a single Java file.
+type file struct {
+	*renderer
+	generators.Renderer
+	imports map[string]bool
+}
+
+// use records the import of a qualified name, returning its simple name.
+func (f *file) use(qualified string) string {
+	f.imports[qualified] = true
+	return qualified[strings.LastIndex(qualified, ".")+1:]
+}
+
+func (f *file) renderRecord(def rewrite.DataDefinition) {
+	var components = make([]string, 0, len(def.Fields))
+	for _, field := range def.Fields {
+		var name = f.names.Name(naming.Field, field.Name)
+
+		// renamed fields keep their declared name when serialized.
+		var property string
+		if name != field.Name {
+			property = fmt.Sprintf("@%s(%q) ", f.use("com.fasterxml.jackson.annotation.JsonProperty"), field.Name)
+		}
+		components = append(components, fmt.Sprintf("    %s%s %s", property, f.typeName(field.Type, false), name))
+	}
+
+	f.Doc("", def.Description)
+	f.Printf("public record %s(\n%s\n) {", f.names.Name(naming.Type, def.Name), strings.Join(components, ",\n"))
+	if len(def.Methods) == 0 {
+		f.Printf("}\n")
+		return
+	}
+
+	f.Printf("\n")
+	for index, method := range def.Methods {
+		if index != 0 {
+			f.Printf("\n")
+		}
+		f.Doc("    ", method.Description)
+		f.Printf("    public %s {\n        throw new UnsupportedOperationException();\n    }\n", f.signature(method))
+	}
+	f.Printf("}\n")
+}
+
+func (f *file) renderInterface(name string, description string, methods []rewrite.MethodDefinition, variables []rewrite.VariableDefinition) {
+	f.Doc("", description)
+	f.Printf("public interface %s {\n", name)
+	for _, variable := range variables {
+		if variable.Type == nil || variable.Assign == nil || variable.Assign.Value == nil {
+			f.SetErr(fmt.Errorf("java: variable %q requires a type and value", variable.Name))
+			continue
+		}
+		// fields of interfaces are constants in Java.
+		f.Doc("    ", variable.Description)
+		f.Printf("    %s %s = %s;\n", f.typeName(variable.Type, false), f.names.Name(naming.Constant, variable.Name), generators.Value(variable.Assign.Value, "null"))
+	}
+	for index, method := range methods {
+		if index != 0 || len(variables) != 0 {
+			f.Printf("\n")
+		}
+		f.Doc("    ", method.Description)
+		f.Printf("    %s;\n", f.signature(method))
+	}
+	f.Printf("}\n")
+}
+
+// signature returns the return type, name and parameters of a method.
+func (f *file) signature(def rewrite.MethodDefinition) string {
+	var args = make([]string, 0, len(def.Arguments))
+	for _, arg := range def.Arguments {
+		args = append(args, fmt.Sprintf("%s %s", f.typeName(arg.Type, false), f.names.Name(naming.Variable, arg.Name)))
+	}
+
+	var returns = "void"
+	switch len(def.Returns) {
+	case 0:
+	case 1:
+		returns = f.typeName(def.Returns[0].Type, false)
+	default:
+		f.SetErr(fmt.Errorf("java: method %q returns more than one value", def.Name))
+	}
+	return fmt.Sprintf("%s %s(%s)", returns, f.names.Name(naming.Method, def.Name), strings.Join(args, ", "))
+}
+
+// typeName returns the Java type for the giving type definition, boxed
+// returns the wrapper of primitive types for use as type arguments.
+func (f *file) typeName(definition rewrite.Applicable, boxed bool) string {
+	if definition == nil {
+		return "Object"
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return f.baseType(def, boxed)
+	case rewrite.DataTypeDefinition:
+		// Java has no type aliases, so scalar aliases resolve to their type.
+		if def.Type != nil {
+			if _, ok := def.Type.Elem().(rewrite.TypeDefinition); ok {
+				return f.typeName(def.Type, boxed)
+			}
+		}
+		if def.Name == "" {
+			return f.typeName(def.Type, boxed)
+		}
+		return f.names.Name(naming.Type, def.Name)
+	case rewrite.DataDefinition:
+		return f.names.Name(naming.Type, def.Name)
+	case rewrite.FutureDefinition:
+		return fmt.Sprintf("%s<%s>", f.use("java.util.concurrent.CompletableFuture"), f.typeName(def.Type, true))
+	case rewrite.StreamDefinition:
+		return fmt.Sprintf("%s.Publisher<%s>", f.use("java.util.concurrent.Flow"), f.typeName(def.Type, true))
+	case rewrite.ChannelDefinition:
+		return fmt.Sprintf("%s<%s>", f.use("java.util.concurrent.BlockingQueue"), f.typeName(def.Type, true))
+	case rewrite.FieldDefinition:
+		return f.typeName(def.Type, boxed)
+	case rewrite.ReturnDefinition:
+		return f.typeName(def.Type, boxed)
+	}
+
+	f.SetErr(fmt.Errorf("java: %T is not a type definition", definition.Elem()))
+	return ""
+}
+
+func (f *file) baseType(def rewrite.TypeDefinition, boxed bool) string {
+	var typ, ok = f.types.Lookup(def)
+	if !ok {
+		f.SetErr(fmt.Errorf("java: base type %q has no Java equivalent", def.Type))
+		return ""
+	}
+	if typ.Import != "" {
+		f.imports[typ.Import] = true
+	}
+	if wrapper, ok := wrappers[typ.Name]; ok && boxed {
+		return wrapper
+	}
+	return typ.Name
+}
+
+// wrappers maps primitive types to their wrapper class.
+var wrappers = map[string]string{
+	"boolean": "Boolean",
+	"byte":    "Byte",
+	"char":    "Character",
+	"short":   "Short",
+	"int":     "Integer",
+	"long":    "Long",
+	"float":   "Float",
+	"double":  "Double",
+}
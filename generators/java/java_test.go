@@ -0,0 +1,150 @@
+package java_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/java"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "A registered user."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "joined"}, Type: &rewrite.TypeDefinition{Type: rewrite.Time}},
+		},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions: []rewrite.Applicable{
+			user,
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Users"},
+				Methods: []rewrite.MethodDefinition{
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "get"},
+						Arguments: []rewrite.FieldDefinition{
+							{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+						},
+						Returns: []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: user}}},
+					},
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "count"},
+						Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: &rewrite.TypeDefinition{Type: rewrite.Integer}}}},
+					},
+				},
+			},
+			&rewrite.VariableDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "LIMIT"},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32},
+				Assign:         &rewrite.AssignmentDefinition{Value: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "10"}}},
+				Constant:       true,
+			},
+		},
+	}
+
+	var files, err = java.Render(pkg, java.Options{Package: "com.example.models"})
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	require.Equal(t, `package com.example.models;
+
+import java.time.Instant;
+
+/** A registered user. */
+public record User(
+    String name,
+    Instant joined
+) {}
+`, string(files["User.java"]))
+	require.Equal(t, `package com.example.models;
+
+import java.util.concurrent.CompletableFuture;
+
+public interface Users {
+    CompletableFuture<User> get(long id);
+
+    CompletableFuture<Long> count();
+}
+`, string(files["Users.java"]))
+	require.Equal(t, `package com.example.models;
+
+public interface Models {
+    int LIMIT = 10;
+}
+`, string(files["Models.java"]))
+}
+
+func TestRenderRenamed(t *testing.T) {
+	var email = &rewrite.DataTypeDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "email_address"},
+		Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "accounts"},
+		Definitions: []rewrite.Applicable{
+			email,
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "account"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "email_address"}, Type: email},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "active"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+			},
+		},
+	}
+
+	var files, err = java.Render(pkg, java.Options{})
+	require.NoError(t, err)
+	var code = string(files["Account.java"])
+	require.Contains(t, code, "    @JsonProperty(\"email_address\") String emailAddress,\n    String active\n")
+	require.Contains(t, code, "import com.fasterxml.jackson.annotation.JsonProperty;\n")
+}
+
+func TestRenderKeywords(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "search"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "find"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "for"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+				Returns: []rewrite.ReturnDefinition{{Type: &rewrite.TypeDefinition{Type: rewrite.String}}},
+			},
+			&rewrite.VariableDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "if"},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+				Assign:         &rewrite.AssignmentDefinition{Value: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "\"any\""}}},
+			},
+		},
+	}
+
+	var out, err = java.Render(pkg, java.Options{})
+	require.NoError(t, err)
+	var code = string(out["Search.java"])
+	require.Contains(t, code, "String find(String for_);")
+}
+
+func TestRenderCollisions(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "user_store"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.MethodDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "open"}},
+		},
+	}
+
+	var files, err = java.Render(pkg, java.Options{})
+	require.NoError(t, err)
+	require.Contains(t, files, "UserStore.java")
+
+	pkg.Definitions = append(pkg.Definitions, &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "user_store"},
+		Methods:        []rewrite.MethodDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "reload"}}},
+	})
+	_, err = java.Render(pkg, java.Options{})
+	require.Error(t, err)
+}
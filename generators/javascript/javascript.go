@@ -0,0 +1,571 @@
+// Package javascript renders a rewrite.PackageDefinition into an ES module
+// with JSDoc type annotations, for consumers which want typed editor hints
+// without TypeScript.
+package javascript
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/naming"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+func init() {
+	typemap.Defaults("javascript", typemap.Table{
+		{Type: rewrite.String}:  {Name: "string"},
+		{Type: rewrite.Rune}:    {Name: "string"},
+		{Type: rewrite.Integer}: {Name: "number"},
+		{Type: rewrite.Decimal}: {Name: "number"},
+		{Type: rewrite.Complex}: {Name: "[number, number]"},
+		{Type: rewrite.Time}:    {Name: "Date"},
+	})
+	naming.Defaults("javascript", naming.Convention{
+		Cases: map[naming.Construct]naming.Case{
+			naming.Type:     naming.Pascal,
+			naming.Field:    naming.Preserve,
+			naming.Method:   naming.Camel,
+			naming.Variable: naming.Camel,
+		},
+		Keywords: naming.Set(
+			"break", "case", "catch", "class", "const", "continue", "debugger",
+			"default", "delete", "do", "else", "enum", "export", "extends",
+			"false", "finally", "for", "function", "if", "implements", "import",
+			"in", "instanceof", "interface", "let", "new", "null", "package",
+			"private", "protected", "public", "return", "static", "super",
+			"switch", "this", "throw", "true", "try", "typeof", "var", "void",
+			"while", "with", "yield",
+		),
+	})
+	generators.Register(generators.Single("javascript", ".js", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg)
+	}))
+}
+
+// Render returns the ES module source for the giving package definition.
+//
+// DataDefinition are rendered as classes, methods as functions whose bodies
+// are translated from their control flow definitions, with methods returning
+// a FutureDefinition rendered as async functions. Statements found directly
+// within the package are rendered as top level module statements.
+func Render(pkg rewrite.PackageDefinition) ([]byte, error) {
+	var r = renderer{types: typemap.For("javascript"), names: naming.For("javascript")}
+	if pkg.Description != "" {
+		r.Printf("%s\n", generators.Comment("// %s", pkg.Description))
+	}
+	if pkg.Version != "" {
+		r.line("// Version: %s", pkg.Version)
+	}
+
+	for _, definition := range pkg.Definitions {
+		r.render(definition)
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return r.Out.Bytes(), nil
+}
+
+type renderer struct {
+	generators.Renderer
+	types typemap.Table
+	names naming.Convention
+	depth int
+}
+
+// line writes a line indented to the current depth.
+func (r *renderer) line(format string, args ...interface{}) {
+	r.Out.WriteString(strings.Repeat("\t", r.depth))
+	fmt.Fprintf(&r.Out, format, args...)
+	r.Out.WriteString("\n")
+}
+
+func (r *renderer) blank() {
+	r.Out.WriteString("\n")
+}
+
+func (r *renderer) render(definition rewrite.Applicable) {
+	if definition == nil {
+		return
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.CommentDefinition:
+		r.blank()
+		r.comment(def)
+	case rewrite.DataDefinition:
+		r.blank()
+		r.renderClass(def)
+	case rewrite.DataTypeDefinition:
+		r.blank()
+		r.doc([]string{def.Description}, fmt.Sprintf("@typedef {%s} %s", r.typeName(def.Type), r.names.Name(naming.Type, def.Name)))
+	case rewrite.TypeDefinition:
+		r.blank()
+		r.doc([]string{def.Description}, fmt.Sprintf("@typedef {%s} %s", r.typeName(definition), r.names.Name(naming.Type, def.Name)))
+	case rewrite.MethodDefinition:
+		r.blank()
+		r.renderFunction("export function", def)
+	case rewrite.VariableDefinition:
+		r.blank()
+		r.doc([]string{def.Description}, r.typeTag(def.Type))
+		r.line("export %s", r.variable(def))
+	case rewrite.ResultDefinition, rewrite.ReturnDefinition, rewrite.CaseDefinition, rewrite.FieldDefinition:
+		// results, returns, cases and fields are only meaningful within
+		// a method, switch or data definition.
+	default:
+		r.statement(definition)
+	}
+}
+
+func (r *renderer) renderClass(def rewrite.DataDefinition) {
+	r.doc([]string{def.Description})
+	r.line("export class %s {", r.names.Name(naming.Type, def.Name))
+	r.depth++
+
+	if len(def.Fields) != 0 {
+		var tags = make([]string, 0, len(def.Fields))
+		var names = make([]string, 0, len(def.Fields))
+		for _, field := range def.Fields {
+			tags = append(tags, r.param(field))
+			names = append(names, r.names.Name(naming.Variable, field.Name))
+		}
+
+		r.doc(nil, tags...)
+		r.line("constructor(%s) {", strings.Join(names, ", "))
+		r.depth++
+		for _, field := range def.Fields {
+			r.doc([]string{field.Description}, fmt.Sprintf("@type {%s}", r.typeName(field.Type)))
+			r.line("this.%s = %s;", r.names.Name(naming.Field, field.Name), r.names.Name(naming.Variable, field.Name))
+		}
+		r.depth--
+		r.line("}")
+	}
+
+	for index, method := range def.Methods {
+		if index != 0 || len(def.Fields) != 0 {
+			r.blank()
+		}
+		r.renderFunction("", method)
+	}
+
+	r.depth--
+	r.line("}")
+}
+
+// renderFunction renders a function or class method, the keyword
+// is empty for class methods.
+func (r *renderer) renderFunction(keyword string, def rewrite.MethodDefinition) {
+	var tags = make([]string, 0, len(def.Arguments)+1)
+	var names = make([]string, 0, len(def.Arguments))
+	for _, arg := range def.Arguments {
+		tags = append(tags, r.param(arg))
+		names = append(names, r.names.Name(naming.Variable, arg.Name))
+	}
+	if len(def.Returns) != 0 {
+		tags = append(tags, fmt.Sprintf("@returns {%s}", r.returnType(def.Returns)))
+	}
+	r.doc([]string{def.Description}, tags...)
+
+	var async = len(def.Returns) == 1 && isFuture(def.Returns[0].Type)
+	switch {
+	case async && keyword != "":
+		keyword = strings.Replace(keyword, "function", "async function", 1)
+	case async:
+		keyword = "async"
+	}
+	if keyword != "" {
+		keyword += " "
+	}
+
+	r.line("%s%s(%s) {", keyword, r.names.Name(naming.Method, def.Name), strings.Join(names, ", "))
+	r.depth++
+	r.body(def.Data)
+	if def.Data == nil && len(def.Returns) != 0 {
+		r.line("throw new Error(\"not implemented\");")
+	}
+	r.depth--
+	r.line("}")
+}
+
+// body renders the statements of a method or control flow body,
+// where a BlockDefinition provides multiple statements.
+func (r *renderer) body(definition rewrite.Applicable) {
+	if definition == nil {
+		return
+	}
+
+	if block, ok := definition.Elem().(rewrite.BlockDefinition); ok {
+		for _, statement := range block.Statements {
+			r.statement(statement)
+		}
+		return
+	}
+	r.statement(definition)
+}
+
+// block renders a braced body following the giving header.
+func (r *renderer) block(header string, definition rewrite.Applicable) {
+	r.line("%s {", header)
+	r.depth++
+	r.body(definition)
+	r.depth--
+	r.line("}")
+}
+
+func (r *renderer) statement(definition rewrite.Applicable) {
+	if definition == nil {
+		return
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.BlockDefinition:
+		r.block("", definition)
+	case rewrite.CommentDefinition:
+		r.comment(def)
+	case rewrite.AnnotationDefinition:
+		r.line("// %s", generators.Annotation(def))
+	case rewrite.VariableDefinition:
+		r.line("%s", r.variable(def))
+	case rewrite.AssignmentDefinition:
+		r.line("%s;", r.assignment(def))
+	case rewrite.IfDefinition:
+		r.block(fmt.Sprintf("if (%s)", r.condition(def.Condition)), def.Body)
+	case rewrite.LoopDefinition:
+		if isEmptyCondition(def.Condition) {
+			r.block("for (;;)", def.Body)
+			return
+		}
+		r.block(fmt.Sprintf("while (%s)", r.condition(def.Condition)), def.Body)
+	case rewrite.ForDefinition:
+		var header = fmt.Sprintf("for (%s; %s; %s)", r.clause(def.Left), r.clause(def.Middle), r.clause(def.End))
+		r.block(header, def.Body)
+	case rewrite.SwitchDefinition:
+		r.renderSwitch(def)
+	case rewrite.ReturnDefinition:
+		if def.Type == nil {
+			r.line("return;")
+			return
+		}
+		r.line("return %s;", r.expr(def.Type))
+	case rewrite.MethodCallDefinition:
+		r.line("%s;", r.call(def))
+	case rewrite.ConditionDefinition:
+		r.line("%s;", r.condition(def))
+	default:
+		r.SetErr(fmt.Errorf("javascript: %T can not be rendered as a statement", def))
+	}
+}
+
+// renderSwitch renders a switch, where a switch without a condition
+// switches on true to match Go's semantics, and each case breaks as
+// Go does not fall through.
+func (r *renderer) renderSwitch(def rewrite.SwitchDefinition) {
+	var subject = "true"
+	if !isEmptyCondition(def.Condition) {
+		subject = r.condition(def.Condition)
+	}
+
+	r.line("switch (%s) {", subject)
+	for _, item := range def.Cases {
+		if isEmptyCondition(item.Condition) {
+			r.line("default:")
+		} else {
+			r.line("case %s:", r.condition(item.Condition))
+		}
+		r.depth++
+		r.body(item.Body)
+		r.line("break;")
+		r.depth--
+	}
+	r.line("}")
+}
+
+// clause renders an init, condition or post clause of a for statement.
+func (r *renderer) clause(definition rewrite.Applicable) string {
+	if definition == nil {
+		return ""
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.VariableDefinition:
+		return strings.TrimSuffix(r.variable(def), ";")
+	case rewrite.AssignmentDefinition:
+		return r.assignment(def)
+	}
+	return r.expr(definition)
+}
+
+func (r *renderer) variable(def rewrite.VariableDefinition) string {
+	var keyword, name = "let", r.names.Name(naming.Variable, def.Name)
+	if def.Constant {
+		keyword, name = "const", r.names.Name(naming.Constant, def.Name)
+	}
+	if def.Assign != nil && def.Assign.Value != nil {
+		return fmt.Sprintf("%s %s = %s;", keyword, name, r.expr(def.Assign.Value))
+	}
+	return fmt.Sprintf("%s %s;", keyword, name)
+}
+
+func (r *renderer) assignment(def rewrite.AssignmentDefinition) string {
+	if def.Short {
+		return fmt.Sprintf("let %s = %s", r.names.Name(naming.Variable, def.Name), r.expr(def.Value))
+	}
+	return fmt.Sprintf("%s = %s", r.names.Name(naming.Variable, def.Name), r.expr(def.Value))
+}
+
+func (r *renderer) call(def rewrite.MethodCallDefinition) string {
+	var name = def.Name
+	if name == "" && def.Method != nil {
+		name = def.Method.Name
+	}
+
+	var args = make([]string, 0, len(def.Arguments))
+	for _, arg := range def.Arguments {
+		if arg.Assign != nil && arg.Assign.Value != nil {
+			args = append(args, r.expr(arg.Assign.Value))
+			continue
+		}
+		args = append(args, r.names.Name(naming.Variable, arg.Name))
+	}
+
+	var call = fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+	switch len(def.Results) {
+	case 0:
+		return call
+	case 1:
+		return fmt.Sprintf("const %s = %s", r.names.Name(naming.Variable, def.Results[0].Name), call)
+	}
+
+	var results = make([]string, 0, len(def.Results))
+	for _, result := range def.Results {
+		results = append(results, r.names.Name(naming.Variable, result.Name))
+	}
+	return fmt.Sprintf("const [%s] = %s", strings.Join(results, ", "), call)
+}
+
+// expr renders the definition as an expression.
+func (r *renderer) expr(definition rewrite.Applicable) string {
+	if definition == nil {
+		return "undefined"
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.Value:
+		if def.Value != nil {
+			return r.expr(def.Value)
+		}
+		return def.Name
+	case rewrite.ConditionDefinition:
+		return r.condition(def)
+	case rewrite.MethodCallDefinition:
+		return r.call(def)
+	case rewrite.AssignmentDefinition:
+		return r.assignment(def)
+	case rewrite.DataDefinition:
+		return fmt.Sprintf("new %s()", r.names.Name(naming.Type, def.Name))
+	}
+
+	if named, ok := definition.(interface{ GetName() string }); ok && named.GetName() != "" {
+		return r.names.Name(naming.Variable, named.GetName())
+	}
+	return "undefined"
+}
+
+// condition renders a condition, nested conditions are wrapped
+// in parentheses to retain their precedence.
+func (r *renderer) condition(def rewrite.ConditionDefinition) string {
+	var operator = def.Operator.Operator
+	if operator == 0 {
+		return r.operand(def.Left)
+	}
+
+	var op, ok = operators[operator]
+	if !ok {
+		r.SetErr(fmt.Errorf("javascript: unknown operator %d", operator))
+		return ""
+	}
+
+	switch operator {
+	case rewrite.Increment, rewrite.Decrement:
+		return r.operand(def.Left) + op
+	case rewrite.BitwiseNot:
+		if def.Left == nil {
+			return op + r.operand(def.Right)
+		}
+		return op + r.operand(def.Left)
+	}
+	return fmt.Sprintf("%s %s %s", r.operand(def.Left), op, r.operand(def.Right))
+}
+
+func (r *renderer) operand(definition rewrite.Applicable) string {
+	if definition == nil {
+		return "undefined"
+	}
+	if condition, ok := definition.Elem().(rewrite.ConditionDefinition); ok && condition.Right != nil {
+		return "(" + r.condition(condition) + ")"
+	}
+	return r.expr(definition)
+}
+
+// operators maps operators to their JavaScript tokens, equality uses the
+// strict comparison operators and the bitwise complement is ~.
+var operators = map[rewrite.Operator]string{
+	rewrite.Equal:              "=",
+	rewrite.NotEquality:        "!==",
+	rewrite.Equality:           "===",
+	rewrite.Increment:          "++",
+	rewrite.Decrement:          "--",
+	rewrite.Multiplication:     "*",
+	rewrite.Subtraction:        "-",
+	rewrite.Division:           "/",
+	rewrite.Addition:           "+",
+	rewrite.SelfMultiplication: "*=",
+	rewrite.SelfSubtraction:    "-=",
+	rewrite.SelfDivision:       "/=",
+	rewrite.SelfAddition:       "+=",
+	rewrite.Modulo:             "%",
+	rewrite.LessThan:           "<",
+	rewrite.GreaterThan:        ">",
+	rewrite.LessThanEqualTo:    "<=",
+	rewrite.GreaterThanEqualTo: ">=",
+	rewrite.ConditionalAnd:     "&&",
+	rewrite.ConditionalOR:      "||",
+	rewrite.BinaryAnd:          "&",
+	rewrite.BinaryOR:           "|",
+	rewrite.BitwiseNot:         "~",
+	rewrite.BitwiseAnd:         "&",
+	rewrite.BitwiseOR:          "|",
+	rewrite.BitwiseXOR:         "^",
+	rewrite.LeftShift:          "<<",
+	rewrite.RightShift:         ">>",
+}
+
+func isEmptyCondition(def rewrite.ConditionDefinition) bool {
+	return def.Left == nil && def.Right == nil
+}
+
+func isFuture(definition rewrite.Applicable) bool {
+	if definition == nil {
+		return false
+	}
+	var _, ok = definition.Elem().(rewrite.FutureDefinition)
+	return ok
+}
+
+func (r *renderer) param(field rewrite.FieldDefinition) string {
+	var tag = fmt.Sprintf("@param {%s} %s", r.typeName(field.Type), r.names.Name(naming.Variable, field.Name))
+	if field.Description != "" {
+		tag += " " + field.Description
+	}
+	return tag
+}
+
+func (r *renderer) typeTag(definition rewrite.Applicable) string {
+	if definition == nil {
+		return ""
+	}
+	return fmt.Sprintf("@type {%s}", r.typeName(definition))
+}
+
+// returnType returns the JSDoc return type for a method, where
+// multiple return values are expressed as a tuple.
+func (r *renderer) returnType(returns []rewrite.ReturnDefinition) string {
+	if len(returns) == 1 {
+		return r.typeName(returns[0].Type)
+	}
+
+	var types = make([]string, 0, len(returns))
+	for _, ret := range returns {
+		types = append(types, r.typeName(ret.Type))
+	}
+	return "[" + strings.Join(types, ", ") + "]"
+}
+
+// typeName returns the JSDoc type for the giving type definition.
+func (r *renderer) typeName(definition rewrite.Applicable) string {
+	if definition == nil {
+		return "*"
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return r.baseType(def)
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return r.typeName(def.Type)
+		}
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.DataDefinition:
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.FutureDefinition:
+		return fmt.Sprintf("Promise<%s>", r.typeName(def.Type))
+	case rewrite.StreamDefinition:
+		return fmt.Sprintf("AsyncIterable<%s>", r.typeName(def.Type))
+	case rewrite.ChannelDefinition:
+		return fmt.Sprintf("AsyncIterable<%s>", r.typeName(def.Type))
+	case rewrite.MethodDefinition:
+		var args = make([]string, 0, len(def.Arguments))
+		for _, arg := range def.Arguments {
+			args = append(args, r.typeName(arg.Type))
+		}
+		if len(def.Returns) == 0 {
+			return fmt.Sprintf("function(%s)", strings.Join(args, ", "))
+		}
+		return fmt.Sprintf("function(%s): %s", strings.Join(args, ", "), r.returnType(def.Returns))
+	case rewrite.FieldDefinition:
+		return r.typeName(def.Type)
+	case rewrite.ReturnDefinition:
+		return r.typeName(def.Type)
+	}
+
+	r.SetErr(fmt.Errorf("javascript: %T is not a type definition", definition.Elem()))
+	return "*"
+}
+
+// baseType returns the JSDoc type of a base type, types with an import
+// are referenced through an import type, e.g import("decimal.js").Decimal.
+func (r *renderer) baseType(def rewrite.TypeDefinition) string {
+	var typ, ok = r.types.Lookup(def)
+	if !ok {
+		return "*"
+	}
+	if typ.Import != "" {
+		return fmt.Sprintf("import(%q).%s", typ.Import, typ.Name)
+	}
+	return typ.Name
+}
+
+// doc renders a JSDoc block from the non-empty descriptions and tags,
+// descriptions of several lines span several lines of the block.
+func (r *renderer) doc(descriptions []string, tags ...string) {
+	var contents []string
+	for _, text := range append(descriptions, tags...) {
+		if text == "" {
+			continue
+		}
+		contents = append(contents, strings.Split(generators.EscapeCommentEnd(text), "\n")...)
+	}
+
+	switch len(contents) {
+	case 0:
+		return
+	case 1:
+		r.line("/** %s */", contents[0])
+		return
+	}
+
+	r.line("/**")
+	for _, line := range contents {
+		r.line("%s", strings.TrimRight(" * "+line, " "))
+	}
+	r.line(" */")
+}
+
+func (r *renderer) comment(def rewrite.CommentDefinition) {
+	for _, line := range def.Contents {
+		r.line("// %s", line)
+	}
+}
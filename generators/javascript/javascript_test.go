@@ -0,0 +1,189 @@
+package javascript_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/javascript"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var age = &rewrite.VariableDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "age"}}
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "A registered user."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "age"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+		},
+		Methods: []rewrite.MethodDefinition{
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "stage"},
+				Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.TypeDefinition{Type: rewrite.String}}},
+				Data: &rewrite.BlockDefinition{
+					Statements: []rewrite.Applicable{
+						&rewrite.IfDefinition{
+							Condition: rewrite.ConditionDefinition{
+								Left:     age,
+								Right:    &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "0"}},
+								Operator: rewrite.OperatorDefinition{Operator: rewrite.Equality},
+							},
+							Body: &rewrite.ReturnDefinition{Type: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: `"unborn"`}}},
+						},
+						&rewrite.SwitchDefinition{
+							Cases: []rewrite.CaseDefinition{
+								{
+									Condition: rewrite.ConditionDefinition{
+										Left:     age,
+										Right:    &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "18"}},
+										Operator: rewrite.OperatorDefinition{Operator: rewrite.LessThan},
+									},
+									Body: &rewrite.ReturnDefinition{Type: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: `"minor"`}}},
+								},
+								{
+									Body: &rewrite.ReturnDefinition{Type: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: `"adult"`}}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions: []rewrite.Applicable{
+			user,
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "fetchUser"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+				Returns: []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: user}}},
+			},
+			&rewrite.ForDefinition{
+				Left: &rewrite.AssignmentDefinition{
+					BaseDefinition: rewrite.BaseDefinition{Name: "i"},
+					Short:          true,
+					Value:          &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "0"}},
+				},
+				Middle: &rewrite.ConditionDefinition{
+					Left:     &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "i"}},
+					Right:    &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "3"}},
+					Operator: rewrite.OperatorDefinition{Operator: rewrite.LessThan},
+				},
+				End: &rewrite.ConditionDefinition{
+					Left:     &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "i"}},
+					Operator: rewrite.OperatorDefinition{Operator: rewrite.Increment},
+				},
+				Body: &rewrite.MethodCallDefinition{
+					BaseDefinition: rewrite.BaseDefinition{Name: "fetchUser"},
+					Arguments:      []rewrite.VariableDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "i"}}},
+				},
+			},
+		},
+	}
+
+	var code, err = javascript.Render(pkg)
+	require.NoError(t, err)
+	require.Equal(t, `
+/** A registered user. */
+export class User {
+	/**
+	 * @param {string} name
+	 * @param {number} age
+	 */
+	constructor(name, age) {
+		/** @type {string} */
+		this.name = name;
+		/** @type {number} */
+		this.age = age;
+	}
+
+	/** @returns {string} */
+	stage() {
+		if (age === 0) {
+			return "unborn";
+		}
+		switch (true) {
+		case age < 18:
+			return "minor";
+			break;
+		default:
+			return "adult";
+			break;
+		}
+	}
+}
+
+/**
+ * @param {string} id
+ * @returns {Promise<User>}
+ */
+export async function fetchUser(id) {
+	throw new Error("not implemented");
+}
+for (let i = 0; i < 3; i++) {
+	fetchUser(i);
+}
+`, string(code))
+}
+
+func TestRenderOperators(t *testing.T) {
+	var mask = &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "mask"}}
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "flags"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "invert"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "mask"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+				},
+				Returns: []rewrite.ReturnDefinition{{Type: &rewrite.TypeDefinition{Type: rewrite.Integer}}},
+				Data: &rewrite.ReturnDefinition{Type: &rewrite.ConditionDefinition{
+					Left:     mask,
+					Operator: rewrite.OperatorDefinition{Operator: rewrite.BitwiseNot},
+				}},
+			},
+		},
+	}
+
+	var code, err = javascript.Render(pkg)
+	require.NoError(t, err)
+	require.Contains(t, string(code), "return ~mask;")
+}
+
+func TestRenderRenamed(t *testing.T) {
+	var email = &rewrite.DataTypeDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "email_address"},
+		Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "accounts"},
+		Definitions: []rewrite.Applicable{
+			email,
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "account"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "email_address"}, Type: email},
+				},
+			},
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "find_account"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "for"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+			},
+		},
+	}
+
+	var out, err = javascript.Render(pkg)
+	require.NoError(t, err)
+	var code = string(out)
+	require.Contains(t, code, "/** @typedef {string} EmailAddress */\n")
+	require.Contains(t, code, "export class Account {\n")
+	require.Contains(t, code, "\t\tthis.email_address = emailAddress;\n")
+	require.Contains(t, code, "export function findAccount(for_) {\n")
+}
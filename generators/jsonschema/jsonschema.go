@@ -0,0 +1,295 @@
+// Package jsonschema renders the data and type definitions of a
+// rewrite.PackageDefinition into JSON Schema (draft 2020-12) documents.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Draft is the JSON Schema dialect of rendered documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// OptionalAnnotation marks a FieldDefinition as not required.
+const OptionalAnnotation = "optional"
+
+// Schema defines a JSON Schema document or subschema.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty" yaml:"$schema,omitempty"`
+	ID          string             `json:"$id,omitempty" yaml:"$id,omitempty"`
+	Ref         string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Title       string             `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Type        string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format      string             `json:"format,omitempty" yaml:"format,omitempty"`
+	MinLength   *int               `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength   *int               `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	PrefixItems []*Schema          `json:"prefixItems,omitempty" yaml:"prefixItems,omitempty"`
+	MinItems    *int               `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems    *int               `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	Defs        map[string]*Schema `json:"$defs,omitempty" yaml:"$defs,omitempty"`
+}
+
+// Options configures how definitions are rendered.
+type Options struct {
+	// BaseURI prefixes the $id of each document, which is the
+	// definition name with a ".schema.json" suffix. No $id is
+	// set if empty.
+	BaseURI string
+}
+
+func init() {
+	typemap.Defaults("jsonschema", typemap.Table{
+		{Type: rewrite.String}:  {Name: "string"},
+		{Type: rewrite.Rune}:    {Name: "string"},
+		{Type: rewrite.Integer}: {Name: "integer"},
+		{Type: rewrite.Decimal}: {Name: "number"},
+		{Type: rewrite.Complex}: {Name: "array"},
+		{Type: rewrite.Time}:    {Name: "string", Format: "date-time"},
+	})
+	generators.Register(generators.Multiple("jsonschema", []string{".schema.json"}, func(pkg rewrite.PackageDefinition) (map[string][]byte, error) {
+		var documents, err = Render(pkg, Options{})
+		if err != nil {
+			return nil, err
+		}
+
+		var files = make(map[string][]byte, len(documents))
+		for name, document := range documents {
+			files[name+".schema.json"] = document
+		}
+		return files, nil
+	}))
+}
+
+// Render returns a JSON Schema document for each DataDefinition and
+// TypeDefinition of the package, keyed by the definition name.
+//
+// Data and data types referenced by a document are declared in its $defs
+// and referenced with $ref, fields are required unless annotated with
+// OptionalAnnotation. References by name must name a declaration of the
+// package.
+func Render(pkg rewrite.PackageDefinition, options Options) (map[string][]byte, error) {
+	var documents = map[string][]byte{}
+	for _, definition := range pkg.Definitions {
+		if definition == nil {
+			continue
+		}
+
+		var name string
+		switch def := definition.Elem().(type) {
+		case rewrite.DataDefinition:
+			name = def.Name
+		case rewrite.TypeDefinition:
+			name = def.Name
+		default:
+			continue
+		}
+
+		var builder = NewBuilder("#/$defs/")
+		builder.Declare(pkg)
+		var schema, err = builder.Document(definition)
+		if err != nil {
+			return nil, err
+		}
+
+		schema.Schema = Draft
+		if options.BaseURI != "" {
+			schema.ID = options.BaseURI + name + ".schema.json"
+		}
+		schema.Defs = builder.Defs
+
+		data, err := json.MarshalIndent(schema, "", "\t")
+		if err != nil {
+			return nil, err
+		}
+		documents[name] = append(data, '\n')
+	}
+	return documents, nil
+}
+
+// Builder converts definitions into schemas, declaring the data and
+// data types they reference in Defs.
+type Builder struct {
+	// RefPrefix prefixes the name of referenced definitions, e.g "#/$defs/".
+	RefPrefix string
+
+	// Defs holds the schemas of referenced definitions by name.
+	Defs map[string]*Schema
+
+	// Types maps base types to their JSON type and format.
+	Types typemap.Table
+
+	// Declarations holds the data and types declared by name, which
+	// references by name, a DataTypeDefinition without a type, resolve to.
+	Declarations map[string]rewrite.Applicable
+}
+
+// NewBuilder returns a Builder which references definitions with refPrefix
+// and maps base types with the jsonschema table of the typemap package.
+func NewBuilder(refPrefix string) *Builder {
+	return &Builder{RefPrefix: refPrefix, Defs: map[string]*Schema{}, Types: typemap.For("jsonschema"), Declarations: map[string]rewrite.Applicable{}}
+}
+
+// Declare adds the data and types declared by the package to Declarations.
+func (b *Builder) Declare(pkg rewrite.PackageDefinition) {
+	for _, definition := range pkg.Definitions {
+		if definition == nil {
+			continue
+		}
+
+		switch def := definition.Elem().(type) {
+		case rewrite.DataDefinition:
+			b.Declarations[def.Name] = definition
+		case rewrite.DataTypeDefinition:
+			if def.Type != nil {
+				b.Declarations[def.Name] = definition
+			}
+		case rewrite.TypeDefinition:
+			b.Declarations[def.Name] = definition
+		}
+	}
+}
+
+// Document returns the schema of a top level definition, where a
+// DataDefinition is rendered as an object instead of a reference.
+func (b *Builder) Document(definition rewrite.Applicable) (*Schema, error) {
+	if definition == nil {
+		return nil, fmt.Errorf("jsonschema: missing type definition")
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.DataDefinition:
+		return b.Object(def)
+	case rewrite.TypeDefinition:
+		var schema, err = b.Schema(definition)
+		if err != nil {
+			return nil, err
+		}
+		schema.Title = def.Name
+		schema.Description = def.Description
+		return schema, nil
+	}
+	return b.Schema(definition)
+}
+
+// Object returns the object schema of a DataDefinition.
+func (b *Builder) Object(def rewrite.DataDefinition) (*Schema, error) {
+	var schema = &Schema{
+		Title:       def.Name,
+		Description: def.Description,
+		Type:        "object",
+		Properties:  map[string]*Schema{},
+	}
+
+	for _, field := range def.Fields {
+		var property, err = b.Schema(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("jsonschema: field %q of %q: %w", field.Name, def.Name, err)
+		}
+		if field.Description != "" {
+			property.Description = field.Description
+		}
+
+		schema.Properties[field.Name] = property
+		if !field.Annotations.Has(OptionalAnnotation) {
+			schema.Required = append(schema.Required, field.Name)
+		}
+	}
+	return schema, nil
+}
+
+// Schema returns the schema for the giving type definition, data and data
+// types are returned as references and declared in Defs. Futures resolve
+// to the schema of their value, streams and channels to arrays.
+func (b *Builder) Schema(definition rewrite.Applicable) (*Schema, error) {
+	if definition == nil {
+		return nil, fmt.Errorf("jsonschema: missing type definition")
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return b.baseType(def)
+	case rewrite.DataDefinition:
+		if _, ok := b.Defs[def.Name]; !ok {
+			// reserve the name first, so self referencing data terminates.
+			b.Defs[def.Name] = nil
+			var object, err = b.Object(def)
+			if err != nil {
+				return nil, err
+			}
+			b.Defs[def.Name] = object
+		}
+		return &Schema{Ref: b.RefPrefix + def.Name}, nil
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return b.Schema(def.Type)
+		}
+		if def.Type == nil {
+			var declared, ok = b.Declarations[def.Name]
+			if !ok {
+				return nil, fmt.Errorf("jsonschema: %q references undeclared data", def.Name)
+			}
+			return b.Schema(declared)
+		}
+		if _, ok := b.Defs[def.Name]; !ok {
+			b.Defs[def.Name] = nil
+			var target, err = b.Document(def.Type)
+			if err != nil {
+				return nil, err
+			}
+			if def.Description != "" {
+				target.Description = def.Description
+			}
+			b.Defs[def.Name] = target
+		}
+		return &Schema{Ref: b.RefPrefix + def.Name}, nil
+	case rewrite.FutureDefinition:
+		return b.Schema(def.Type)
+	case rewrite.StreamDefinition:
+		var items, err = b.Schema(def.Type)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case rewrite.ChannelDefinition:
+		var items, err = b.Schema(def.Type)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case rewrite.FieldDefinition:
+		return b.Schema(def.Type)
+	case rewrite.ReturnDefinition:
+		return b.Schema(def.Type)
+	}
+	return nil, fmt.Errorf("jsonschema: %T can not be expressed as a schema", definition.Elem())
+}
+
+// baseType returns the schema of a base type from its mapping, runes and
+// complex numbers mapped to their default types are further constrained
+// to a single character and a pair of numbers.
+func (b *Builder) baseType(def rewrite.TypeDefinition) (*Schema, error) {
+	var typ, ok = b.Types.Lookup(def)
+	if !ok {
+		return nil, fmt.Errorf("jsonschema: unknown base type %q", def.Type)
+	}
+
+	var schema = &Schema{Type: typ.Name, Format: typ.Format}
+	switch {
+	case def.Type == rewrite.Rune && typ.Name == "string":
+		var one = 1
+		schema.MinLength, schema.MaxLength = &one, &one
+	case def.Type == rewrite.Complex && typ.Name == "array":
+		var two = 2
+		schema.PrefixItems = []*Schema{{Type: "number"}, {Type: "number"}}
+		schema.MinItems, schema.MaxItems = &two, &two
+	}
+	return schema, nil
+}
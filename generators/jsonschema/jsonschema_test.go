@@ -0,0 +1,131 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/jsonschema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var address = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "Address"},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "city"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+		},
+	}
+
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "A registered user."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "age"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "joined", Description: "When the user signed up."},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.Time},
+				Annotations:    rewrite.Annotations{{BaseDefinition: rewrite.BaseDefinition{Name: jsonschema.OptionalAnnotation}}},
+			},
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "home"},
+				Type:           &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "Home"}, Type: address},
+			},
+		},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions: []rewrite.Applicable{
+			user,
+			&rewrite.TypeDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Score", Description: "A game score."},
+				Type:           rewrite.Decimal,
+			},
+		},
+	}
+
+	var documents, err = jsonschema.Render(pkg, jsonschema.Options{BaseURI: "https://example.com/"})
+	require.NoError(t, err)
+	require.Len(t, documents, 2)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/User.schema.json",
+		"title": "User",
+		"description": "A registered user.",
+		"type": "object",
+		"properties": {
+			"age": {"type": "integer"},
+			"joined": {"type": "string", "format": "date-time", "description": "When the user signed up."},
+			"home": {"$ref": "#/$defs/Home"}
+		},
+		"required": ["age", "home"],
+		"$defs": {
+			"Home": {
+				"title": "Address",
+				"type": "object",
+				"properties": {"city": {"type": "string"}},
+				"required": ["city"]
+			}
+		}
+	}`, string(documents["User"]))
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/Score.schema.json",
+		"title": "Score",
+		"description": "A game score.",
+		"type": "number"
+	}`, string(documents["Score"]))
+}
+
+func TestBuilderSelfReference(t *testing.T) {
+	var node = &rewrite.DataDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "Node"}}
+	node.Fields = []rewrite.FieldDefinition{
+		{BaseDefinition: rewrite.BaseDefinition{Name: "children"}, Type: &rewrite.StreamDefinition{Type: node}},
+	}
+
+	var builder = jsonschema.NewBuilder("#/$defs/")
+	var schema, err = builder.Schema(node)
+	require.NoError(t, err)
+	require.Equal(t, "#/$defs/Node", schema.Ref)
+	require.Equal(t, "#/$defs/Node", builder.Defs["Node"].Properties["children"].Items.Ref)
+}
+
+func TestRenderReferenceByName(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Order"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "buyer"}, Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "User"}}},
+				},
+			},
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "User"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+			},
+		},
+	}
+
+	var documents, err = jsonschema.Render(pkg, jsonschema.Options{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Order",
+		"type": "object",
+		"properties": {"buyer": {"$ref": "#/$defs/User"}},
+		"required": ["buyer"],
+		"$defs": {
+			"User": {
+				"title": "User",
+				"type": "object",
+				"properties": {"name": {"type": "string"}},
+				"required": ["name"]
+			}
+		}
+	}`, string(documents["Order"]))
+
+	pkg.Definitions = pkg.Definitions[:1]
+	_, err = jsonschema.Render(pkg, jsonschema.Options{})
+	require.Error(t, err)
+}
@@ -0,0 +1,357 @@
+// Package kotlin renders a rewrite.PackageDefinition into a Kotlin file of
+// data classes and interfaces for JVM and Android consumers.
+package kotlin
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/naming"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Options configures how definitions are rendered.
+type Options struct {
+	// Package sets the Kotlin package, defaults to the package definition name.
+	Package string
+}
+
+func init() {
+	typemap.Defaults("kotlin", typemap.Table{
+		{Type: rewrite.String}:                         {Name: "String"},
+		{Type: rewrite.Rune}:                           {Name: "Char"},
+		{Type: rewrite.Integer}:                        {Name: "Long"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "Int"},
+		{Type: rewrite.Decimal}:                        {Name: "Double"},
+		{Type: rewrite.Decimal, Memory: rewrite.Bit32}: {Name: "Float"},
+		{Type: rewrite.Time}:                           {Name: "Instant", Import: "java.time.Instant"},
+	})
+	naming.Defaults("kotlin", naming.Convention{
+		Cases: map[naming.Construct]naming.Case{
+			naming.Type:     naming.Pascal,
+			naming.Field:    naming.Camel,
+			naming.Method:   naming.Camel,
+			naming.Constant: naming.Screaming,
+			naming.Variable: naming.Camel,
+		},
+		Keywords: naming.Set(
+			"as", "break", "class", "continue", "do", "else", "false", "for",
+			"fun", "if", "in", "interface", "is", "null", "object", "package",
+			"return", "super", "this", "throw", "true", "try", "typealias",
+			"typeof", "val", "var", "when", "while",
+		),
+		Escape: func(name string) string {
+			return "`" + name + "`"
+		},
+	})
+	generators.Register(generators.Single("kotlin", ".kt", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg, Options{})
+	}))
+}
+
+// Render returns the Kotlin source for the giving package definition.
+//
+// DataDefinition with fields are rendered as kotlinx.serialization
+// Serializable data classes, while those with only methods are rendered as
+// interfaces. Instant has no serializer of its own, so files serializing one
+// declare InstantSerializer, which codes it as an ISO-8601 string. Methods returning a
+// FutureDefinition are rendered as suspend functions and StreamDefinition as
+// Flow. Statements found directly within the package are ignored.
+func Render(pkg rewrite.PackageDefinition, options Options) ([]byte, error) {
+	var r = renderer{
+		Renderer: generators.Renderer{
+			DocFormat: "/** %s */",
+			DocBlock:  [3]string{"/**", " * ", " */"},
+			DocEscape: generators.EscapeCommentEnd,
+		},
+		imports: map[string]bool{},
+		types:   typemap.For("kotlin"),
+		names:   naming.For("kotlin"),
+	}
+	for _, definition := range pkg.Definitions {
+		r.render(definition)
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	var out bytes.Buffer
+	if pkg.Description != "" {
+		fmt.Fprintf(&out, "%s\n", generators.Comment("// %s", pkg.Description))
+	}
+	if pkg.Version != "" {
+		fmt.Fprintf(&out, "// Version: %s\n", pkg.Version)
+	}
+
+	// the serializer of Instant applies to the whole file, as file
+	// annotations must precede the package directive.
+	if r.serializable && r.instant {
+		fmt.Fprintf(&out, "@file:%s(InstantSerializer::class)\n\n", r.use("kotlinx.serialization.UseSerializers"))
+		r.renderInstantSerializer()
+	}
+
+	var name = options.Package
+	if name == "" {
+		name = pkg.Name
+	}
+	if name != "" {
+		fmt.Fprintf(&out, "package %s\n", name)
+	}
+
+	var imports = make([]string, 0, len(r.imports))
+	for path := range r.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	if len(imports) != 0 {
+		out.WriteString("\n")
+	}
+	for _, path := range imports {
+		fmt.Fprintf(&out, "import %s\n", path)
+	}
+
+	out.Write(r.Out.Bytes())
+	return out.Bytes(), nil
+}
+
+type renderer struct {
+	generators.Renderer
+	types   typemap.Table
+	names   naming.Convention
+	imports map[string]bool
+
+	// serializable is true if a data class was rendered, and instant if
+	// a type resolved to java.time.Instant.
+	serializable bool
+	instant      bool
+}
+
+// use records the import of a qualified name, returning its simple name.
+func (r *renderer) use(qualified string) string {
+	r.imports[qualified] = true
+	return qualified[strings.LastIndex(qualified, ".")+1:]
+}
+
+func (r *renderer) render(definition rewrite.Applicable) {
+	if definition == nil {
+		return
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.CommentDefinition:
+		r.Printf("\n")
+		for _, line := range def.Contents {
+			r.Printf("// %s\n", line)
+		}
+	case rewrite.DataDefinition:
+		if len(def.Fields) == 0 {
+			r.renderInterface(def)
+			return
+		}
+		r.renderDataClass(def)
+	case rewrite.DataTypeDefinition:
+		r.Doc("", def.Description)
+		r.Printf("typealias %s = %s\n", r.names.Name(naming.Type, def.Name), r.typeName(def.Type))
+	case rewrite.TypeDefinition:
+		r.Doc("", def.Description)
+		r.Printf("typealias %s = %s\n", r.names.Name(naming.Type, def.Name), r.typeName(definition))
+	case rewrite.MethodDefinition:
+		r.Doc("", def.Description)
+		r.Printf("%s = TODO()\n", r.signature(def))
+	case rewrite.VariableDefinition:
+		r.renderVariable(def)
+	}
+}
+
+func (r *renderer) renderDataClass(def rewrite.DataDefinition) {
+	r.serializable = true
+	r.Doc("", def.Description)
+	r.Printf("@%s\ndata class %s(\n", r.use("kotlinx.serialization.Serializable"), r.names.Name(naming.Type, def.Name))
+	for _, field := range def.Fields {
+		var name = r.names.Name(naming.Field, field.Name)
+		r.Doc("    ", field.Description)
+
+		// renamed fields keep their declared name when serialized.
+		if name != field.Name {
+			r.Printf("    @%s(%q)\n", r.use("kotlinx.serialization.SerialName"), field.Name)
+		}
+		r.Printf("    val %s: %s,\n", name, r.typeName(field.Type))
+	}
+	r.Printf(")")
+
+	if len(def.Methods) == 0 {
+		r.Printf("\n")
+		return
+	}
+
+	r.Printf(" {\n")
+	for index, method := range def.Methods {
+		if index != 0 {
+			r.Printf("\n")
+		}
+		r.Doc("    ", method.Description)
+		r.Printf("    %s = TODO()\n", r.signature(method))
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderInterface(def rewrite.DataDefinition) {
+	r.Doc("", def.Description)
+	r.Printf("interface %s {\n", r.names.Name(naming.Type, def.Name))
+	for index, method := range def.Methods {
+		if index != 0 {
+			r.Printf("\n")
+		}
+		r.Doc("    ", method.Description)
+		r.Printf("    %s\n", r.signature(method))
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderVariable(def rewrite.VariableDefinition) {
+	var keyword = "var"
+	if def.Constant {
+		keyword = "val"
+	}
+
+	var name = r.names.Name(naming.Variable, def.Name)
+	if def.Constant {
+		name = r.names.Name(naming.Constant, def.Name)
+	}
+
+	r.Doc("", def.Description)
+	r.Printf("%s %s", keyword, name)
+	if def.Type != nil {
+		r.Printf(": %s", r.typeName(def.Type))
+	}
+	if def.Assign != nil && def.Assign.Value != nil {
+		r.Printf(" = %s", generators.Value(def.Assign.Value, "null"))
+	}
+	r.Printf("\n")
+}
+
+// signature returns the declaration of a function, methods returning a
+// single FutureDefinition are suspend functions returning its value.
+func (r *renderer) signature(def rewrite.MethodDefinition) string {
+	var args = make([]string, 0, len(def.Arguments))
+	for _, arg := range def.Arguments {
+		args = append(args, fmt.Sprintf("%s: %s", r.names.Name(naming.Variable, arg.Name), r.typeName(arg.Type)))
+	}
+
+	var name = r.names.Name(naming.Method, def.Name)
+	var returns = def.Returns
+	if len(returns) == 1 && returns[0].Type != nil {
+		if future, ok := returns[0].Type.Elem().(rewrite.FutureDefinition); ok {
+			return fmt.Sprintf("suspend fun %s(%s): %s", name, strings.Join(args, ", "), r.typeName(future.Type))
+		}
+	}
+	return fmt.Sprintf("fun %s(%s): %s", name, strings.Join(args, ", "), r.returnType(def.Name, returns))
+}
+
+// returnType returns the Kotlin return type for a method, where two and
+// three return values are expressed as a Pair and Triple.
+func (r *renderer) returnType(name string, returns []rewrite.ReturnDefinition) string {
+	var types = make([]string, 0, len(returns))
+	for _, ret := range returns {
+		types = append(types, r.typeName(ret.Type))
+	}
+
+	switch len(types) {
+	case 0:
+		return "Unit"
+	case 1:
+		return types[0]
+	case 2:
+		return fmt.Sprintf("Pair<%s>", strings.Join(types, ", "))
+	case 3:
+		return fmt.Sprintf("Triple<%s>", strings.Join(types, ", "))
+	}
+	r.SetErr(fmt.Errorf("kotlin: method %q returns more than three values", name))
+	return ""
+}
+
+// typeName returns the Kotlin type for the giving type definition.
+func (r *renderer) typeName(definition rewrite.Applicable) string {
+	if definition == nil {
+		return "Any"
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return r.baseType(def)
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return r.typeName(def.Type)
+		}
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.DataDefinition:
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.FutureDefinition:
+		return fmt.Sprintf("%s<%s>", r.use("kotlinx.coroutines.Deferred"), r.typeName(def.Type))
+	case rewrite.StreamDefinition:
+		return fmt.Sprintf("%s<%s>", r.use("kotlinx.coroutines.flow.Flow"), r.typeName(def.Type))
+	case rewrite.ChannelDefinition:
+		switch def.Direction {
+		case rewrite.IncomingDirectional:
+			return fmt.Sprintf("%s<%s>", r.use("kotlinx.coroutines.channels.ReceiveChannel"), r.typeName(def.Type))
+		case rewrite.OutgoingDirectional:
+			return fmt.Sprintf("%s<%s>", r.use("kotlinx.coroutines.channels.SendChannel"), r.typeName(def.Type))
+		default:
+			return fmt.Sprintf("%s<%s>", r.use("kotlinx.coroutines.channels.Channel"), r.typeName(def.Type))
+		}
+	case rewrite.MethodDefinition:
+		var args = make([]string, 0, len(def.Arguments))
+		for _, arg := range def.Arguments {
+			args = append(args, r.typeName(arg.Type))
+		}
+		return fmt.Sprintf("(%s) -> %s", strings.Join(args, ", "), r.returnType(def.Name, def.Returns))
+	case rewrite.FieldDefinition:
+		return r.typeName(def.Type)
+	case rewrite.ReturnDefinition:
+		return r.typeName(def.Type)
+	}
+
+	r.SetErr(fmt.Errorf("kotlin: %T is not a type definition", definition.Elem()))
+	return ""
+}
+
+func (r *renderer) baseType(def rewrite.TypeDefinition) string {
+	var typ, ok = r.types.Lookup(def)
+	if !ok {
+		r.SetErr(fmt.Errorf("kotlin: base type %q has no Kotlin equivalent", def.Type))
+		return ""
+	}
+	if typ.Import != "" {
+		r.imports[typ.Import] = true
+	}
+	if typ.Import == "java.time.Instant" {
+		r.instant = true
+	}
+	return typ.Name
+}
+
+// renderInstantSerializer renders the serializer coding Instant as an
+// ISO-8601 string.
+func (r *renderer) renderInstantSerializer() {
+	r.Printf(`
+object InstantSerializer : %s<Instant> {
+    override val descriptor: %s = %s("Instant", %s.STRING)
+
+    override fun serialize(encoder: %s, value: Instant) = encoder.encodeString(value.toString())
+
+    override fun deserialize(decoder: %s): Instant = Instant.parse(decoder.decodeString())
+}
+`,
+		r.use("kotlinx.serialization.KSerializer"),
+		r.use("kotlinx.serialization.descriptors.SerialDescriptor"),
+		r.use("kotlinx.serialization.descriptors.PrimitiveSerialDescriptor"),
+		r.use("kotlinx.serialization.descriptors.PrimitiveKind"),
+		r.use("kotlinx.serialization.encoding.Encoder"),
+		r.use("kotlinx.serialization.encoding.Decoder"),
+	)
+}
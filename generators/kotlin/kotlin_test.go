@@ -0,0 +1,137 @@
+package kotlin_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/kotlin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "A registered user."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "joined"}, Type: &rewrite.TypeDefinition{Type: rewrite.Time}},
+		},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions: []rewrite.Applicable{
+			user,
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Users"},
+				Methods: []rewrite.MethodDefinition{
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "get"},
+						Arguments: []rewrite.FieldDefinition{
+							{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+						},
+						Returns: []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: user}}},
+					},
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "watch"},
+						Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.StreamDefinition{Type: user}}},
+					},
+				},
+			},
+		},
+	}
+
+	var code, err = kotlin.Render(pkg, kotlin.Options{Package: "com.example.models"})
+	require.NoError(t, err)
+	require.Equal(t, `@file:UseSerializers(InstantSerializer::class)
+
+package com.example.models
+
+import java.time.Instant
+import kotlinx.coroutines.flow.Flow
+import kotlinx.serialization.KSerializer
+import kotlinx.serialization.Serializable
+import kotlinx.serialization.UseSerializers
+import kotlinx.serialization.descriptors.PrimitiveKind
+import kotlinx.serialization.descriptors.PrimitiveSerialDescriptor
+import kotlinx.serialization.descriptors.SerialDescriptor
+import kotlinx.serialization.encoding.Decoder
+import kotlinx.serialization.encoding.Encoder
+
+/** A registered user. */
+@Serializable
+data class User(
+    val name: String,
+    val joined: Instant,
+)
+
+interface Users {
+    suspend fun get(id: Long): User
+
+    fun watch(): Flow<User>
+}
+
+object InstantSerializer : KSerializer<Instant> {
+    override val descriptor: SerialDescriptor = PrimitiveSerialDescriptor("Instant", PrimitiveKind.STRING)
+
+    override fun serialize(encoder: Encoder, value: Instant) = encoder.encodeString(value.toString())
+
+    override fun deserialize(decoder: Decoder): Instant = Instant.parse(decoder.decodeString())
+}
+`, string(code))
+}
+
+func TestRenderRenamed(t *testing.T) {
+	var email = &rewrite.DataTypeDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "email_address"},
+		Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "accounts"},
+		Definitions: []rewrite.Applicable{
+			email,
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "account"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "email_address"}, Type: email},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "active"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+			},
+		},
+	}
+
+	var out, err = kotlin.Render(pkg, kotlin.Options{})
+	require.NoError(t, err)
+	var code = string(out)
+	require.Contains(t, code, "typealias EmailAddress = String\n")
+	require.Contains(t, code, "    @SerialName(\"email_address\")\n    val emailAddress: EmailAddress,\n    val active: String,\n")
+	require.Contains(t, code, "import kotlinx.serialization.SerialName\n")
+	require.Contains(t, code, "@Serializable\ndata class Account(\n")
+	require.NotContains(t, code, "InstantSerializer")
+}
+
+func TestRenderKeywords(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "search"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "find"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "for"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+				Returns: []rewrite.ReturnDefinition{{Type: &rewrite.TypeDefinition{Type: rewrite.String}}},
+			},
+			&rewrite.VariableDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "if"},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+				Assign:         &rewrite.AssignmentDefinition{Value: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "\"any\""}}},
+			},
+		},
+	}
+
+	var out, err = kotlin.Render(pkg, kotlin.Options{})
+	require.NoError(t, err)
+	var code = string(out)
+	require.Contains(t, code, "fun find(`for`: String): String")
+	require.Contains(t, code, "var `if`: String = \"any\"")
+}
@@ -0,0 +1,301 @@
+// Package naming converts the names of definitions into the identifier
+// conventions of each generator, by construct and with reserved keywords
+// escaped.
+//
+// Generators declare their convention with Defaults when their package is
+// imported, and convert names with the convention returned by For when
+// rendering. The original name of a definition is left untouched, so
+// generators can still use it where the name is part of the data format,
+// e.g the json tag of a Go field:
+//
+//	var convention = naming.For("go")
+//	convention.Name(naming.Field, "user_id") // UserID
+package naming
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Case defines how the words of a name are joined into an identifier.
+type Case int
+
+const (
+	// Preserve keeps names as they are declared.
+	Preserve Case = iota
+
+	// Camel joins words with the first lower cased and the others
+	// capitalized, e.g userId.
+	Camel
+
+	// Pascal joins capitalized words, e.g UserId.
+	Pascal
+
+	// Snake joins lower cased words with underscores, e.g user_id.
+	Snake
+
+	// Screaming joins upper cased words with underscores, e.g USER_ID.
+	Screaming
+)
+
+func (c Case) String() string {
+	switch c {
+	case Preserve:
+		return "preserve"
+	case Camel:
+		return "camel"
+	case Pascal:
+		return "pascal"
+	case Snake:
+		return "snake"
+	case Screaming:
+		return "screaming"
+	default:
+		return "unknown"
+	}
+}
+
+// Apply returns the name converted to the case.
+func (c Case) Apply(name string) string {
+	return Convention{Cases: map[Construct]Case{Type: c}}.Name(Type, name)
+}
+
+// Construct identifies what a name declares.
+type Construct int
+
+const (
+	// Type is the name of data and data type declarations.
+	Type Construct = iota
+
+	// Field is the name of a field of data.
+	Field
+
+	// Method is the name of a method of data or of a package.
+	Method
+
+	// Constant is the name of a constant variable.
+	Constant
+
+	// Variable is the name of a variable or of a method argument, which
+	// share a construct as method bodies refer to arguments as variables.
+	Variable
+)
+
+// Convention defines the identifiers of a generator.
+type Convention struct {
+	// Cases holds the case of each construct, constructs without a case
+	// are preserved.
+	Cases map[Construct]Case
+
+	// Keywords holds the reserved words of the target, which are escaped
+	// when a converted name matches one of them.
+	Keywords map[string]bool
+
+	// Initialisms holds the words written fully upper cased when
+	// capitalized, e.g "id" for UserID in Go.
+	Initialisms map[string]bool
+
+	// Escape returns a reserved word escaped, e.g "type_" or "`type`",
+	// names matching keywords are suffixed with an underscore if nil.
+	Escape func(name string) string
+}
+
+// Name returns the name converted for the construct, escaped if it is a
+// keyword of the convention. Only the last part of a qualified name is
+// converted, e.g "models.user_id", and names without letters or digits are
+// returned unchanged.
+func (c Convention) Name(construct Construct, name string) string {
+	if index := strings.LastIndex(name, "."); index != -1 {
+		return name[:index+1] + c.Name(construct, name[index+1:])
+	}
+
+	var converted = c.convert(c.Cases[construct], name)
+	if !c.Keywords[converted] {
+		return converted
+	}
+	if c.Escape != nil {
+		return c.Escape(converted)
+	}
+	return converted + "_"
+}
+
+func (c Convention) convert(cs Case, name string) string {
+	var words = Words(name)
+	if cs == Preserve || len(words) == 0 {
+		return name
+	}
+
+	for index, word := range words {
+		switch cs {
+		case Camel:
+			if index == 0 {
+				words[index] = strings.ToLower(word)
+				continue
+			}
+			words[index] = c.capitalize(word)
+		case Pascal:
+			words[index] = c.capitalize(word)
+		case Snake:
+			words[index] = strings.ToLower(word)
+		case Screaming:
+			words[index] = strings.ToUpper(word)
+		}
+	}
+
+	var joined string
+	if cs == Snake || cs == Screaming {
+		joined = strings.Join(words, "_")
+	} else {
+		joined = strings.Join(words, "")
+	}
+
+	// identifiers can not start with a digit in any of the targets.
+	if unicode.IsDigit([]rune(joined)[0]) {
+		joined = "_" + joined
+	}
+	return joined
+}
+
+func (c Convention) capitalize(word string) string {
+	var lower = strings.ToLower(word)
+	if c.Initialisms[lower] {
+		return strings.ToUpper(word)
+	}
+	var runes = []rune(lower)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// Words splits a name into its words, on any character which is not a
+// letter or digit and where the case changes, e.g "userID", "user_id" and
+// "UserId" are all split into "user" and "id". Digits belong to the word
+// before them.
+func Words(name string) []string {
+	var words []string
+	var runes = []rune(name)
+	var start = -1
+	for index, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start != -1 {
+				words = append(words, string(runes[start:index]))
+				start = -1
+			}
+			continue
+		}
+		if start == -1 {
+			start = index
+			continue
+		}
+
+		var previous = runes[index-1]
+		var upper = unicode.IsUpper(r)
+		switch {
+		case upper && (unicode.IsLower(previous) || unicode.IsDigit(previous)):
+			// userId, base64Url
+		case upper && unicode.IsUpper(previous) && index+1 < len(runes) && unicode.IsLower(runes[index+1]):
+			// HTTPServer
+		default:
+			continue
+		}
+		words = append(words, string(runes[start:index]))
+		start = index
+	}
+	if start != -1 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// Conventions holds the conventions of generators by generator name, it
+// is safe for concurrent use.
+type Conventions struct {
+	mu        sync.RWMutex
+	defaults  map[string]Convention
+	overrides map[string]map[Construct]Case
+}
+
+// New returns a new empty Conventions.
+func New() *Conventions {
+	return &Conventions{
+		defaults:  map[string]Convention{},
+		overrides: map[string]map[Construct]Case{},
+	}
+}
+
+// Defaults sets the default convention of a generator.
+func (c *Conventions) Defaults(generator string, convention Convention) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaults[generator] = convention
+}
+
+// Override sets the case of a construct for a generator, taking
+// precedence over its defaults.
+func (c *Conventions) Override(generator string, construct Construct, cs Case) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.overrides[generator] == nil {
+		c.overrides[generator] = map[Construct]Case{}
+	}
+	c.overrides[generator][construct] = cs
+}
+
+// Reset removes the overrides of a generator.
+func (c *Conventions) Reset(generator string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.overrides, generator)
+}
+
+// Convention returns the convention of a generator, its defaults with
+// overrides applied. Generators without a convention preserve names.
+func (c *Conventions) Convention(generator string) Convention {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var convention = c.defaults[generator]
+	var cases = make(map[Construct]Case, len(convention.Cases))
+	for construct, cs := range convention.Cases {
+		cases[construct] = cs
+	}
+	for construct, cs := range c.overrides[generator] {
+		cases[construct] = cs
+	}
+	convention.Cases = cases
+	return convention
+}
+
+// Default holds the conventions used by the generators of this module.
+var Default = New()
+
+// Defaults sets the default convention of a generator in Default.
+func Defaults(generator string, convention Convention) {
+	Default.Defaults(generator, convention)
+}
+
+// Override sets the case of a construct for a generator in Default.
+func Override(generator string, construct Construct, cs Case) {
+	Default.Override(generator, construct, cs)
+}
+
+// Reset removes the overrides of a generator in Default.
+func Reset(generator string) {
+	Default.Reset(generator)
+}
+
+// For returns the convention of a generator in Default.
+func For(generator string) Convention {
+	return Default.Convention(generator)
+}
+
+// Set returns a set of the giving words, for declaring keywords and
+// initialisms.
+func Set(words ...string) map[string]bool {
+	var set = make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
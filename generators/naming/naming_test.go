@@ -0,0 +1,56 @@
+package naming_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite/generators/naming"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWords(t *testing.T) {
+	require.Equal(t, []string{"user", "id"}, naming.Words("user_id"))
+	require.Equal(t, []string{"user", "ID"}, naming.Words("userID"))
+	require.Equal(t, []string{"HTTP", "Server"}, naming.Words("HTTPServer"))
+	require.Equal(t, []string{"base64", "Url"}, naming.Words("base64Url"))
+	require.Empty(t, naming.Words("__"))
+}
+
+func TestCase(t *testing.T) {
+	require.Equal(t, "userId", naming.Camel.Apply("user_id"))
+	require.Equal(t, "UserId", naming.Pascal.Apply("user-id"))
+	require.Equal(t, "user_id", naming.Snake.Apply("UserID"))
+	require.Equal(t, "USER_ID", naming.Screaming.Apply("userId"))
+	require.Equal(t, "user_id", naming.Preserve.Apply("user_id"))
+	require.Equal(t, "_2fa_code", naming.Snake.Apply("2faCode"))
+}
+
+func TestConvention(t *testing.T) {
+	var conventions = naming.New()
+	conventions.Defaults("go", naming.Convention{
+		Cases:       map[naming.Construct]naming.Case{naming.Field: naming.Pascal},
+		Keywords:    naming.Set("type"),
+		Initialisms: naming.Set("id"),
+	})
+
+	var convention = conventions.Convention("go")
+	require.Equal(t, "UserID", convention.Name(naming.Field, "user_id"))
+	require.Equal(t, "user_id", convention.Name(naming.Type, "user_id"))
+	require.Equal(t, "type_", convention.Name(naming.Type, "type"))
+	require.Equal(t, "type_", convention.Name(naming.Variable, "type"))
+	require.Equal(t, "models.UserID", convention.Name(naming.Field, "models.userId"))
+
+	conventions.Override("go", naming.Type, naming.Pascal)
+	require.Equal(t, "UserID", conventions.Convention("go").Name(naming.Type, "user_id"))
+
+	conventions.Reset("go")
+	require.Equal(t, "user_id", conventions.Convention("go").Name(naming.Type, "user_id"))
+
+	var kotlin = naming.Convention{
+		Cases:    map[naming.Construct]naming.Case{naming.Field: naming.Camel},
+		Keywords: naming.Set("object"),
+		Escape: func(name string) string {
+			return "`" + name + "`"
+		},
+	}
+	require.Equal(t, "`object`", kotlin.Name(naming.Field, "Object"))
+}
@@ -0,0 +1,322 @@
+// Package openapi renders the methods and data of a rewrite.PackageDefinition
+// into an OpenAPI 3.1 document describing a HTTP API.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/jsonschema"
+	"gopkg.in/yaml.v2"
+)
+
+// Version is the OpenAPI version of rendered documents.
+const Version = "3.1.0"
+
+// OperationAnnotation marks a MethodDefinition as an operation, its content
+// being the HTTP verb and path of the operation, e.g "GET /users/{id}".
+// Methods without it are not part of the HTTP API.
+const OperationAnnotation = "http"
+
+// Format defines the encoding of a rendered document.
+type Format int
+
+const (
+	YAML Format = iota
+	JSON
+)
+
+// Options configures how definitions are rendered.
+type Options struct {
+	Format Format
+}
+
+// Document defines an OpenAPI document.
+type Document struct {
+	OpenAPI    string              `json:"openapi" yaml:"openapi"`
+	Info       Info                `json:"info" yaml:"info"`
+	Paths      map[string]PathItem `json:"paths" yaml:"paths"`
+	Components *Components         `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+// Info defines the metadata of an API.
+type Info struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Version     string `json:"version" yaml:"version"`
+}
+
+// PathItem defines the operations of a path by lowercase HTTP verb.
+type PathItem map[string]*Operation
+
+// Operation defines a single API operation on a path.
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Description string              `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses" yaml:"responses"`
+}
+
+// Parameter defines a path or query parameter of an operation.
+type Parameter struct {
+	Name        string             `json:"name" yaml:"name"`
+	In          string             `json:"in" yaml:"in"`
+	Description string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool               `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema      *jsonschema.Schema `json:"schema" yaml:"schema"`
+}
+
+// RequestBody defines the body of an operation.
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty" yaml:"required,omitempty"`
+	Content  map[string]MediaType `json:"content" yaml:"content"`
+}
+
+// Response defines a response of an operation.
+type Response struct {
+	Description string               `json:"description" yaml:"description"`
+	Content     map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// MediaType defines the schema of a request or response body.
+type MediaType struct {
+	Schema *jsonschema.Schema `json:"schema" yaml:"schema"`
+}
+
+// Components defines the reusable schemas of a document.
+type Components struct {
+	Schemas map[string]*jsonschema.Schema `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+}
+
+const jsonMedia = "application/json"
+
+var pathParameters = regexp.MustCompile(`{([^}]+)}`)
+
+// verbs holds the HTTP verbs a path item can declare operations for.
+var verbs = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+func init() {
+	generators.Register(generators.Single("openapi", ".yaml", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg, Options{})
+	}))
+}
+
+// Render returns the OpenAPI document for the giving package definition.
+func Render(pkg rewrite.PackageDefinition, options Options) ([]byte, error) {
+	var document, err = Build(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Format == JSON {
+		data, err := json.MarshalIndent(document, "", "\t")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+	return yaml.Marshal(document)
+}
+
+// Build returns the OpenAPI document for the giving package definition.
+//
+// Every method annotated with OperationAnnotation, whether declared on the
+// package or on a DataDefinition, becomes an operation identified by the
+// method name, which must be unique across the document. Methods declared
+// on a DataDefinition are tagged with its name. Arguments named in the path
+// become path parameters, the remaining arguments become query parameters
+// for GET, HEAD and DELETE operations and the request body otherwise. Every
+// DataDefinition with fields is declared in components/schemas.
+func Build(pkg rewrite.PackageDefinition) (*Document, error) {
+	var version = pkg.Version
+	if version == "" {
+		version = "0.0.0"
+	}
+
+	var document = &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: pkg.Name, Description: pkg.Description, Version: version},
+		Paths:   map[string]PathItem{},
+	}
+
+	var builder = jsonschema.NewBuilder("#/components/schemas/")
+	builder.Declare(pkg)
+	var schemas = map[string]*jsonschema.Schema{}
+	for _, definition := range pkg.Definitions {
+		if definition == nil {
+			continue
+		}
+
+		switch def := definition.Elem().(type) {
+		case rewrite.MethodDefinition:
+			if err := addOperation(document, builder, "", def); err != nil {
+				return nil, err
+			}
+		case rewrite.DataDefinition:
+			for _, method := range def.Methods {
+				if err := addOperation(document, builder, def.Name, method); err != nil {
+					return nil, err
+				}
+			}
+			if len(def.Fields) == 0 {
+				continue
+			}
+
+			var schema, err = builder.Object(def)
+			if err != nil {
+				return nil, err
+			}
+			schemas[def.Name] = schema
+		}
+	}
+
+	for name, schema := range builder.Defs {
+		if _, ok := schemas[name]; !ok {
+			schemas[name] = schema
+		}
+	}
+	if len(schemas) != 0 {
+		document.Components = &Components{Schemas: schemas}
+	}
+	return document, nil
+}
+
+func addOperation(document *Document, builder *jsonschema.Builder, tag string, method rewrite.MethodDefinition) error {
+	var annotation, ok = method.Annotations.Get(OperationAnnotation)
+	if !ok {
+		return nil
+	}
+
+	var route = strings.Fields(annotation.Content)
+	if len(route) != 2 {
+		return fmt.Errorf("openapi: method %q has invalid operation %q, expected verb and path", method.Name, annotation.Content)
+	}
+
+	var verb, path = strings.ToLower(route[0]), route[1]
+	if !verbs[verb] {
+		return fmt.Errorf("openapi: method %q has unknown HTTP verb %q", method.Name, route[0])
+	}
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("openapi: method %q has path %q, which must start with /", method.Name, path)
+	}
+	for _, item := range document.Paths {
+		for _, other := range item {
+			if other.OperationID == method.Name {
+				return fmt.Errorf("openapi: method %q reuses the operationId of another operation", method.Name)
+			}
+		}
+	}
+
+	var operation = &Operation{
+		OperationID: method.Name,
+		Description: method.Description,
+		Responses:   map[string]Response{},
+	}
+	if tag != "" {
+		operation.Tags = []string{tag}
+	}
+
+	var parameters = pathParameters.FindAllStringSubmatch(path, -1)
+	var inPath = map[string]bool{}
+	for _, match := range parameters {
+		inPath[match[1]] = true
+	}
+
+	var body []rewrite.FieldDefinition
+	for _, arg := range method.Arguments {
+		var schema, err = builder.Schema(arg.Type)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case inPath[arg.Name]:
+			delete(inPath, arg.Name)
+			operation.Parameters = append(operation.Parameters, Parameter{
+				Name:        arg.Name,
+				In:          "path",
+				Description: arg.Description,
+				Required:    true,
+				Schema:      schema,
+			})
+		case verb == "get" || verb == "head" || verb == "delete":
+			operation.Parameters = append(operation.Parameters, Parameter{
+				Name:        arg.Name,
+				In:          "query",
+				Description: arg.Description,
+				Required:    !arg.Annotations.Has(jsonschema.OptionalAnnotation),
+				Schema:      schema,
+			})
+		default:
+			body = append(body, arg)
+		}
+	}
+
+	for _, match := range parameters {
+		if inPath[match[1]] {
+			return fmt.Errorf("openapi: method %q has no argument for path parameter %q", method.Name, match[1])
+		}
+	}
+
+	if len(body) != 0 {
+		var schema, err = requestSchema(builder, body)
+		if err != nil {
+			return err
+		}
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{jsonMedia: {Schema: schema}},
+		}
+	}
+
+	switch len(method.Returns) {
+	case 0:
+		operation.Responses["204"] = Response{Description: "No Content"}
+	case 1:
+		var schema, err = builder.Schema(method.Returns[0].Type)
+		if err != nil {
+			return err
+		}
+		operation.Responses["200"] = Response{
+			Description: "OK",
+			Content:     map[string]MediaType{jsonMedia: {Schema: schema}},
+		}
+	default:
+		return fmt.Errorf("openapi: method %q must return at most one value", method.Name)
+	}
+
+	if document.Paths[path] == nil {
+		document.Paths[path] = PathItem{}
+	}
+	if _, ok := document.Paths[path][verb]; ok {
+		return fmt.Errorf("openapi: method %q redeclares operation %q", method.Name, annotation.Content)
+	}
+	document.Paths[path][verb] = operation
+	return nil
+}
+
+// requestSchema returns the schema of a request body, a single data
+// argument is the body itself while multiple arguments are properties
+// of the body.
+func requestSchema(builder *jsonschema.Builder, args []rewrite.FieldDefinition) (*jsonschema.Schema, error) {
+	if len(args) == 1 {
+		return builder.Schema(args[0].Type)
+	}
+
+	var body = rewrite.DataDefinition{Fields: args}
+	var schema, err = builder.Object(body)
+	if err != nil {
+		return nil, err
+	}
+	schema.Title = ""
+	return schema, nil
+}
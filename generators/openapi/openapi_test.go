@@ -0,0 +1,150 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/openapi"
+	"github.com/stretchr/testify/require"
+)
+
+func operation(content string) rewrite.Annotations {
+	return rewrite.Annotations{{BaseDefinition: rewrite.BaseDefinition{Name: openapi.OperationAnnotation}, Content: content}}
+}
+
+func userPackage() rewrite.PackageDefinition {
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User"},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+		},
+	}
+
+	var id = rewrite.FieldDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}}
+	var service = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "Users"},
+		Methods: []rewrite.MethodDefinition{
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "getUser", Description: "Returns a user."},
+				Arguments:      []rewrite.FieldDefinition{id},
+				Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: user}}},
+				Annotations:    operation("GET /users/{id}"),
+			},
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "updateUser"},
+				Arguments:      []rewrite.FieldDefinition{id, {BaseDefinition: rewrite.BaseDefinition{Name: "user"}, Type: user}},
+				Annotations:    operation("PUT /users/{id}"),
+			},
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "internal"},
+			},
+		},
+	}
+
+	return rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "users", Version: "1.0.0"},
+		Definitions:    []rewrite.Applicable{user, service},
+	}
+}
+
+func TestRender(t *testing.T) {
+	var document, err = openapi.Render(userPackage(), openapi.Options{})
+	require.NoError(t, err)
+	require.Equal(t, `openapi: 3.1.0
+info:
+  title: users
+  version: 1.0.0
+paths:
+  /users/{id}:
+    get:
+      operationId: getUser
+      description: Returns a user.
+      tags:
+      - Users
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+    put:
+      operationId: updateUser
+      tags:
+      - Users
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+      responses:
+        "204":
+          description: No Content
+components:
+  schemas:
+    User:
+      title: User
+      type: object
+      properties:
+        name:
+          type: string
+      required:
+      - name
+`, string(document))
+
+	document, err = openapi.Render(userPackage(), openapi.Options{Format: openapi.JSON})
+	require.NoError(t, err)
+	require.Contains(t, string(document), `"openapi": "3.1.0"`)
+}
+
+func TestRenderMissingPathArgument(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "getUser"},
+				Annotations:    operation("GET /users/{id}"),
+			},
+		},
+	}
+
+	var _, err = openapi.Render(pkg, openapi.Options{})
+	require.Error(t, err)
+}
+
+func TestRenderInvalidOperations(t *testing.T) {
+	for _, methods := range [][]rewrite.MethodDefinition{
+		{{BaseDefinition: rewrite.BaseDefinition{Name: "getUser"}, Annotations: operation("FETCH /users")}},
+		{{BaseDefinition: rewrite.BaseDefinition{Name: "getUser"}, Annotations: operation("GET users")}},
+		{{BaseDefinition: rewrite.BaseDefinition{Name: "getUser"}, Annotations: operation("GET")}},
+		{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "getUser"}, Annotations: operation("GET /users")},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "getUser"}, Annotations: operation("HEAD /users")},
+		},
+		{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "getUser"}, Annotations: operation("GET /users")},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "listUsers"}, Annotations: operation("GET /users")},
+		},
+	} {
+		var pkg = rewrite.PackageDefinition{
+			Definitions: []rewrite.Applicable{
+				&rewrite.DataDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "Users"}, Methods: methods},
+			},
+		}
+
+		var _, err = openapi.Render(pkg, openapi.Options{})
+		require.Error(t, err)
+	}
+}
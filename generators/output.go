@@ -0,0 +1,229 @@
+package generators
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/influx6/rewrite"
+)
+
+// Imports maps the name of packages referenced by a package to their
+// import path.
+type Imports map[string]string
+
+// ProjectGenerator is implemented by generators which resolve references
+// to the other packages of a Project into imports.
+type ProjectGenerator interface {
+	Generator
+
+	// GenerateImports returns the files rendered for the giving package
+	// definition, which references the packages in imports.
+	GenerateImports(pkg rewrite.PackageDefinition, imports Imports) ([]File, error)
+}
+
+// Project defines packages generated together, where definitions of a
+// package reference data of another package by qualifying the name of a
+// DataTypeDefinition with the package name, e.g "models.User".
+type Project struct {
+	// Module is the import path packages are generated under, the import
+	// path of a package being the module joined with the package name.
+	Module   string
+	Packages []rewrite.PackageDefinition
+}
+
+// Generate runs the generator against every package of the project,
+// placing the files of each package in a directory named after it.
+//
+// Only generators implementing ProjectGenerator, like the Go generator,
+// resolve qualified references into imports. Other generators receive
+// qualified names as declared, e.g "models.User", and render them as
+// their naming convention converts them.
+func (p Project) Generate(generator Generator) (*Output, error) {
+	var output = NewOutput()
+	for _, pkg := range p.Packages {
+		var imports, err = p.Imports(pkg)
+		if err != nil {
+			return nil, err
+		}
+
+		var files []File
+		if project, ok := generator.(ProjectGenerator); ok {
+			files, err = project.GenerateImports(pkg, imports)
+		} else {
+			files, err = generator.Generate(pkg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("generators: package %q: %w", pkg.Name, err)
+		}
+
+		if err := output.Add(pkg.Name, files...); err != nil {
+			return nil, err
+		}
+	}
+	return output, nil
+}
+
+// Imports returns the packages of the project referenced by the giving
+// package, it returns an error if a package of the project is unnamed,
+// declared twice or references a package outside of the project.
+func (p Project) Imports(pkg rewrite.PackageDefinition) (Imports, error) {
+	var packages = map[string]bool{}
+	for _, other := range p.Packages {
+		if other.Name == "" {
+			return nil, fmt.Errorf("generators: project package requires a name")
+		}
+		if packages[other.Name] {
+			return nil, fmt.Errorf("generators: package %q is declared twice", other.Name)
+		}
+		packages[other.Name] = true
+	}
+
+	var imports = Imports{}
+	var err error
+	for _, definition := range pkg.Definitions {
+		references(definition, func(name string) {
+			var index = strings.Index(name, ".")
+			if index == -1 || name[:index] == pkg.Name || err != nil {
+				return
+			}
+			if !packages[name[:index]] {
+				err = fmt.Errorf("generators: package %q references %q of unknown package", pkg.Name, name)
+				return
+			}
+			imports[name[:index]] = path.Join(p.Module, name[:index])
+		})
+	}
+	return imports, err
+}
+
+// references calls visit with the name of every data and data type
+// referenced by the definition.
+func references(definition rewrite.Applicable, visit func(name string)) {
+	if definition == nil {
+		return
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.DataTypeDefinition:
+		if def.Name != "" && def.Type == nil {
+			visit(def.Name)
+		}
+		references(def.Type, visit)
+	case rewrite.DataDefinition:
+		for _, field := range def.Fields {
+			references(field.Type, visit)
+		}
+		for _, method := range def.Methods {
+			references(&method, visit)
+		}
+	case rewrite.MethodDefinition:
+		for _, arg := range def.Arguments {
+			references(arg.Type, visit)
+		}
+		for _, ret := range def.Returns {
+			references(ret.Type, visit)
+		}
+	case rewrite.VariableDefinition:
+		references(def.Type, visit)
+	case rewrite.FieldDefinition:
+		references(def.Type, visit)
+	case rewrite.ReturnDefinition:
+		references(def.Type, visit)
+	case rewrite.FutureDefinition:
+		references(def.Type, visit)
+	case rewrite.StreamDefinition:
+		references(def.Type, visit)
+	case rewrite.ChannelDefinition:
+		references(def.Type, visit)
+	}
+}
+
+// Output collects generated files by their slash separated path.
+type Output struct {
+	files map[string][]byte
+}
+
+// NewOutput returns a new empty Output.
+func NewOutput() *Output {
+	return &Output{files: map[string][]byte{}}
+}
+
+// Add adds the files under the giving directory, it returns an error if a
+// file path is added twice or is outside of the output.
+func (o *Output) Add(dir string, files ...File) error {
+	for _, file := range files {
+		var name = path.Join(dir, file.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("generators: file %q is outside of the output", name)
+		}
+		if _, ok := o.files[name]; ok {
+			return fmt.Errorf("generators: file %q is generated twice", name)
+		}
+		o.files[name] = file.Content
+	}
+	return nil
+}
+
+// Files returns the files of the output sorted by path.
+func (o *Output) Files() []File {
+	var files = make([]File, 0, len(o.files))
+	for name, content := range o.files {
+		files = append(files, File{Name: name, Content: content})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	return files
+}
+
+// Write writes the files of the output under the giving directory,
+// creating directories as needed. Files of dir which are not part of the
+// output are left untouched, including files generated by a previous
+// write which the output no longer holds.
+//
+// Each file is written to a temporary file next to it, which is then
+// renamed over the file, so readers observe either the previous or the
+// new content of a file and a failure never leaves a partially written
+// file behind. The files are replaced one by one, a failure may leave some
+// of them replaced and others not.
+func (o *Output) Write(dir string) error {
+	for _, file := range o.Files() {
+		if err := writeFile(filepath.Join(dir, filepath.FromSlash(file.Name)), file.Content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile replaces the file at path with content by renaming a
+// temporary file written in the same directory over it.
+func writeFile(path string, content []byte) error {
+	var dir = filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	var temp, err = ioutil.TempFile(dir, "."+filepath.Base(path)+"-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Chmod(0644); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
@@ -0,0 +1,107 @@
+package generators_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/stretchr/testify/require"
+)
+
+var services = rewrite.PackageDefinition{
+	BaseDefinition: rewrite.BaseDefinition{Name: "services"},
+	Definitions: []rewrite.Applicable{
+		&rewrite.DataDefinition{
+			BaseDefinition: rewrite.BaseDefinition{Name: "Users"},
+			Methods: []rewrite.MethodDefinition{
+				{
+					BaseDefinition: rewrite.BaseDefinition{Name: "Get"},
+					Returns: []rewrite.ReturnDefinition{
+						{Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "models.User"}}},
+					},
+				},
+				{
+					BaseDefinition: rewrite.BaseDefinition{Name: "Self"},
+					Returns: []rewrite.ReturnDefinition{
+						{Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "services.Users"}}},
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestProject(t *testing.T) {
+	var project = generators.Project{
+		Module:   "github.com/acme/api",
+		Packages: []rewrite.PackageDefinition{models, services},
+	}
+
+	var imports, err = project.Imports(services)
+	require.NoError(t, err)
+	require.Equal(t, generators.Imports{"models": "github.com/acme/api/models"}, imports)
+
+	output, err := project.Generate(generators.Go)
+	require.NoError(t, err)
+
+	var files = output.Files()
+	require.Len(t, files, 2)
+	require.Equal(t, "models/models.go", files[0].Name)
+	require.Equal(t, "services/services.go", files[1].Name)
+	require.Contains(t, string(files[1].Content), `models "github.com/acme/api/models"`)
+	require.Contains(t, string(files[1].Content), "Get() models.User")
+	require.Contains(t, string(files[1].Content), "Self() Users")
+
+	var dir, dirErr = ioutil.TempDir("", "output")
+	require.NoError(t, dirErr)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, output.Write(filepath.Join(dir, "api")))
+	content, err := ioutil.ReadFile(filepath.Join(dir, "api", "services", "services.go"))
+	require.NoError(t, err)
+	require.Equal(t, files[1].Content, content)
+
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// writing again replaces the written files and leaves other files alone.
+	var foreign = filepath.Join(dir, "api", "README.md")
+	require.NoError(t, ioutil.WriteFile(foreign, []byte("# api\n"), 0644))
+
+	var models = generators.NewOutput()
+	require.NoError(t, models.Add("models", generators.File{Name: "models.go", Content: []byte("package models\n")}))
+	require.NoError(t, models.Write(filepath.Join(dir, "api")))
+
+	content, err = ioutil.ReadFile(foreign)
+	require.NoError(t, err)
+	require.Equal(t, "# api\n", string(content))
+	content, err = ioutil.ReadFile(filepath.Join(dir, "api", "services", "services.go"))
+	require.NoError(t, err)
+	require.Equal(t, files[1].Content, content)
+	content, err = ioutil.ReadFile(filepath.Join(dir, "api", "models", "models.go"))
+	require.NoError(t, err)
+	require.Equal(t, "package models\n", string(content))
+
+	// no temporary files are left behind.
+	entries, err = ioutil.ReadDir(filepath.Join(dir, "api", "models"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestProjectUnknownPackage(t *testing.T) {
+	var project = generators.Project{Packages: []rewrite.PackageDefinition{services}}
+
+	var _, err = project.Generate(generators.Go)
+	require.Error(t, err)
+}
+
+func TestOutputAdd(t *testing.T) {
+	var output = generators.NewOutput()
+	require.NoError(t, output.Add("models", generators.File{Name: "user.go"}))
+	require.Error(t, output.Add("models", generators.File{Name: "user.go"}))
+	require.Error(t, output.Add("models", generators.File{Name: "../../user.go"}))
+}
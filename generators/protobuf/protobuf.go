@@ -0,0 +1,264 @@
+// Package protobuf renders a rewrite.PackageDefinition into a proto3
+// schema with messages and gRPC services.
+package protobuf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Annotations understood by the protobuf backend.
+const (
+	// ServiceAnnotation marks a DataDefinition as a service, whose
+	// methods are rendered as rpc methods.
+	ServiceAnnotation = "service"
+
+	// FieldNumberAnnotation pins the field number of a FieldDefinition,
+	// its content being the number, e.g "3".
+	FieldNumberAnnotation = "field_number"
+)
+
+// fieldNumbers numbers the fields of messages, where 19000 to 19999 are
+// reserved by the protobuf implementation.
+var fieldNumbers = generators.FieldNumbers{
+	Annotation: FieldNumberAnnotation,
+	Max:        1<<29 - 1,
+	Reserved:   [][2]int{{19000, 19999}},
+}
+
+const (
+	emptyImport     = "google/protobuf/empty.proto"
+	timestampImport = "google/protobuf/timestamp.proto"
+)
+
+// Options configures how definitions are rendered.
+type Options struct {
+	// GoPackage sets the go_package option of the schema if not empty.
+	GoPackage string
+}
+
+func init() {
+	typemap.Defaults("protobuf", typemap.Table{
+		{Type: rewrite.String}:                         {Name: "string"},
+		{Type: rewrite.Rune}:                           {Name: "int32"},
+		{Type: rewrite.Integer}:                        {Name: "int64"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "int32"},
+		{Type: rewrite.Decimal}:                        {Name: "double"},
+		{Type: rewrite.Decimal, Memory: rewrite.Bit32}: {Name: "float"},
+		{Type: rewrite.Time}:                           {Name: "google.protobuf.Timestamp", Import: timestampImport},
+	})
+	generators.Register(generators.Single("protobuf", ".proto", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg, Options{})
+	}))
+}
+
+// Render returns the proto3 schema for the giving package definition.
+//
+// DataDefinition are rendered as messages, where every field must pin its
+// number with FieldNumberAnnotation, so numbers stay stable as fields are
+// added, reordered or removed. DataDefinition annotated with
+// ServiceAnnotation are rendered as services, where each method must take
+// and return at most one message, which may be a StreamDefinition. Messages
+// can not declare methods and services can not declare fields.
+func Render(pkg rewrite.PackageDefinition, options Options) ([]byte, error) {
+	var r = renderer{Renderer: generators.Renderer{DocFormat: "// %s"}, imports: map[string]bool{}, types: typemap.For("protobuf")}
+	for _, definition := range pkg.Definitions {
+		r.render(definition)
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	var out bytes.Buffer
+	if pkg.Description != "" {
+		fmt.Fprintf(&out, "%s\n", generators.Comment("// %s", pkg.Description))
+	}
+	if pkg.Version != "" {
+		fmt.Fprintf(&out, "// Version: %s\n", pkg.Version)
+	}
+	out.WriteString("syntax = \"proto3\";\n")
+	if pkg.Name != "" {
+		fmt.Fprintf(&out, "\npackage %s;\n", pkg.Name)
+	}
+	if options.GoPackage != "" {
+		fmt.Fprintf(&out, "\noption go_package = %q;\n", options.GoPackage)
+	}
+
+	var imports = make([]string, 0, len(r.imports))
+	for path := range r.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	if len(imports) != 0 {
+		out.WriteString("\n")
+	}
+	for _, path := range imports {
+		fmt.Fprintf(&out, "import %q;\n", path)
+	}
+
+	out.Write(r.Out.Bytes())
+	return out.Bytes(), nil
+}
+
+type renderer struct {
+	generators.Renderer
+	types   typemap.Table
+	imports map[string]bool
+}
+
+func (r *renderer) render(definition rewrite.Applicable) {
+	if definition == nil {
+		return
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.CommentDefinition:
+		r.Printf("\n")
+		for _, line := range def.Contents {
+			r.Printf("// %s\n", line)
+		}
+	case rewrite.DataDefinition:
+		if def.Annotations.Has(ServiceAnnotation) {
+			r.renderService(def)
+			return
+		}
+		r.renderMessage(def)
+	case rewrite.MethodDefinition:
+		r.SetErr(fmt.Errorf("protobuf: method %q must be declared on a service", def.Name))
+	}
+}
+
+func (r *renderer) renderMessage(def rewrite.DataDefinition) {
+	if len(def.Methods) != 0 {
+		r.SetErr(fmt.Errorf("protobuf: message %q declares methods, which must be declared on a service", def.Name))
+		return
+	}
+
+	var numbers, err = fieldNumbers.Numbers(def.Fields)
+	if err != nil {
+		r.SetErr(fmt.Errorf("protobuf: message %q: %w", def.Name, err))
+		return
+	}
+
+	r.Doc("", def.Description)
+	r.Printf("message %s {\n", def.Name)
+	for index, field := range def.Fields {
+		r.Doc("\t", field.Description)
+		r.Printf("\t%s %s = %d;\n", r.fieldType(field.Type), field.Name, numbers[index])
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderService(def rewrite.DataDefinition) {
+	if len(def.Fields) != 0 {
+		r.SetErr(fmt.Errorf("protobuf: service %q declares fields, which must be declared on a message", def.Name))
+		return
+	}
+
+	r.Doc("", def.Description)
+	r.Printf("service %s {\n", def.Name)
+	for _, method := range def.Methods {
+		r.Doc("\t", method.Description)
+		r.Printf("\trpc %s(%s) returns (%s);\n", method.Name, r.rpcArgument(method), r.rpcReturn(method))
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) rpcArgument(method rewrite.MethodDefinition) string {
+	switch len(method.Arguments) {
+	case 0:
+		r.imports[emptyImport] = true
+		return "google.protobuf.Empty"
+	case 1:
+		return r.rpcType(method.Arguments[0].Type)
+	}
+	r.SetErr(fmt.Errorf("protobuf: rpc %q must take a single message", method.Name))
+	return ""
+}
+
+func (r *renderer) rpcReturn(method rewrite.MethodDefinition) string {
+	switch len(method.Returns) {
+	case 0:
+		r.imports[emptyImport] = true
+		return "google.protobuf.Empty"
+	case 1:
+		return r.rpcType(method.Returns[0].Type)
+	}
+	r.SetErr(fmt.Errorf("protobuf: rpc %q must return a single message", method.Name))
+	return ""
+}
+
+// rpcType returns the message type of a rpc argument or return, streams
+// are rendered as streaming rpc and futures as unary rpc.
+func (r *renderer) rpcType(definition rewrite.Applicable) string {
+	if definition == nil {
+		r.SetErr(fmt.Errorf("protobuf: missing rpc type definition"))
+		return ""
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.StreamDefinition:
+		return "stream " + r.rpcType(def.Type)
+	case rewrite.FutureDefinition:
+		return r.rpcType(def.Type)
+	case rewrite.DataDefinition:
+		return def.Name
+	case rewrite.DataTypeDefinition:
+		return def.Name
+	}
+
+	r.SetErr(fmt.Errorf("protobuf: rpc type %T must be a message", definition.Elem()))
+	return ""
+}
+
+// fieldType returns the proto3 type of a message field, streams are
+// rendered as repeated fields.
+func (r *renderer) fieldType(definition rewrite.Applicable) string {
+	if definition == nil {
+		r.SetErr(fmt.Errorf("protobuf: missing type definition"))
+		return ""
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return r.baseType(def)
+	case rewrite.DataTypeDefinition:
+		// proto3 has no type aliases, so scalar aliases resolve to their type.
+		if def.Type != nil {
+			if _, ok := def.Type.Elem().(rewrite.TypeDefinition); ok {
+				return r.fieldType(def.Type)
+			}
+		}
+		if def.Name == "" {
+			return r.fieldType(def.Type)
+		}
+		return def.Name
+	case rewrite.DataDefinition:
+		return def.Name
+	case rewrite.StreamDefinition:
+		return "repeated " + r.fieldType(def.Type)
+	case rewrite.FieldDefinition:
+		return r.fieldType(def.Type)
+	}
+
+	r.SetErr(fmt.Errorf("protobuf: %T can not be expressed as a field type", definition.Elem()))
+	return ""
+}
+
+func (r *renderer) baseType(def rewrite.TypeDefinition) string {
+	var typ, ok = r.types.Lookup(def)
+	if !ok {
+		r.SetErr(fmt.Errorf("protobuf: base type %q has no proto3 equivalent", def.Type))
+		return ""
+	}
+	if typ.Import != "" {
+		r.imports[typ.Import] = true
+	}
+	return typ.Name
+}
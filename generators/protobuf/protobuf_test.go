@@ -0,0 +1,119 @@
+package protobuf_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/protobuf"
+	"github.com/stretchr/testify/require"
+)
+
+func number(content string) rewrite.Annotations {
+	return rewrite.Annotations{{BaseDefinition: rewrite.BaseDefinition{Name: protobuf.FieldNumberAnnotation}, Content: content}}
+}
+
+func TestRender(t *testing.T) {
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "A registered user."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}, Annotations: number("2")},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}, Annotations: number("1")},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "joined"}, Type: &rewrite.TypeDefinition{Type: rewrite.Time}, Annotations: number("3")},
+		},
+	}
+
+	var lookup = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "Lookup"},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32}, Annotations: number("1")},
+		},
+	}
+
+	var service = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "Users"},
+		Annotations:    rewrite.Annotations{{BaseDefinition: rewrite.BaseDefinition{Name: protobuf.ServiceAnnotation}}},
+		Methods: []rewrite.MethodDefinition{
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Get"},
+				Arguments:      []rewrite.FieldDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "lookup"}, Type: lookup}},
+				Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: user}}},
+			},
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Watch"},
+				Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.StreamDefinition{Type: user}}},
+			},
+		},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions:    []rewrite.Applicable{user, lookup, service},
+	}
+
+	var schema, err = protobuf.Render(pkg, protobuf.Options{GoPackage: "example.com/models"})
+	require.NoError(t, err)
+	require.Equal(t, `syntax = "proto3";
+
+package models;
+
+option go_package = "example.com/models";
+
+import "google/protobuf/empty.proto";
+import "google/protobuf/timestamp.proto";
+
+// A registered user.
+message User {
+	string name = 2;
+	int64 id = 1;
+	google.protobuf.Timestamp joined = 3;
+}
+
+message Lookup {
+	int32 id = 1;
+}
+
+service Users {
+	rpc Get(Lookup) returns (User);
+	rpc Watch(google.protobuf.Empty) returns (stream User);
+}
+`, string(schema))
+}
+
+func TestRenderDuplicateFieldNumber(t *testing.T) {
+	var number = number("1")
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "User"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}, Annotations: number},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}, Annotations: number},
+				},
+			},
+		},
+	}
+
+	var _, err = protobuf.Render(pkg, protobuf.Options{})
+	require.Error(t, err)
+}
+
+func TestRenderInvalidMessages(t *testing.T) {
+	var id = &rewrite.TypeDefinition{Type: rewrite.Integer}
+	for _, def := range []*rewrite.DataDefinition{
+		{Fields: []rewrite.FieldDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: id}}},
+		{Fields: []rewrite.FieldDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: id, Annotations: number("19500")}}},
+		{Fields: []rewrite.FieldDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: id, Annotations: number("536870912")}}},
+		{
+			Fields:  []rewrite.FieldDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: id, Annotations: number("1")}},
+			Methods: []rewrite.MethodDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "Reload"}}},
+		},
+		{
+			Fields:      []rewrite.FieldDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: id, Annotations: number("1")}},
+			Annotations: rewrite.Annotations{{BaseDefinition: rewrite.BaseDefinition{Name: protobuf.ServiceAnnotation}}},
+		},
+	} {
+		def.Name = "User"
+		var _, err = protobuf.Render(rewrite.PackageDefinition{Definitions: []rewrite.Applicable{def}}, protobuf.Options{})
+		require.Error(t, err)
+	}
+}
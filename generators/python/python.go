@@ -0,0 +1,312 @@
+// Package python renders a rewrite.PackageDefinition into a Python module
+// of dataclasses or pydantic models with typed function stubs.
+package python
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/naming"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Options configures how definitions are rendered.
+type Options struct {
+	// Pydantic renders DataDefinition as pydantic models instead
+	// of dataclasses.
+	Pydantic bool
+}
+
+func init() {
+	typemap.Defaults("python", typemap.Table{
+		{Type: rewrite.String}:  {Name: "str"},
+		{Type: rewrite.Rune}:    {Name: "str"},
+		{Type: rewrite.Integer}: {Name: "int"},
+		{Type: rewrite.Decimal}: {Name: "float"},
+		{Type: rewrite.Complex}: {Name: "complex"},
+		{Type: rewrite.Time}:    {Name: "datetime", Import: "datetime"},
+	})
+	naming.Defaults("python", naming.Convention{
+		Cases: map[naming.Construct]naming.Case{
+			naming.Type:     naming.Pascal,
+			naming.Field:    naming.Snake,
+			naming.Method:   naming.Snake,
+			naming.Constant: naming.Screaming,
+			naming.Variable: naming.Snake,
+		},
+		Keywords: naming.Set(
+			"False", "None", "True", "and", "as", "assert", "async", "await",
+			"break", "class", "continue", "def", "del", "elif", "else",
+			"except", "finally", "for", "from", "global", "if", "import", "in",
+			"is", "lambda", "nonlocal", "not", "or", "pass", "raise", "return",
+			"try", "while", "with", "yield",
+		),
+	})
+	generators.Register(generators.Single("python", ".py", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg, Options{})
+	}))
+}
+
+// Render returns the Python source for the giving package definition.
+//
+// DataDefinition are rendered as classes with their fields and method
+// stubs, FutureDefinition as Awaitable and StreamDefinition as
+// AsyncIterator. Statements found directly within the package are ignored.
+func Render(pkg rewrite.PackageDefinition, options Options) ([]byte, error) {
+	var r = renderer{options: options, imports: map[string]map[string]bool{}, types: typemap.For("python"), names: naming.For("python")}
+	for _, definition := range pkg.Definitions {
+		r.render(definition)
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	var out bytes.Buffer
+	var docs []string
+	if pkg.Description != "" {
+		docs = append(docs, pkg.Description)
+	}
+	if pkg.Version != "" {
+		docs = append(docs, "Version: "+pkg.Version)
+	}
+	if len(docs) != 0 {
+		fmt.Fprintf(&out, "%s\n\n", docstring("", strings.Join(docs, "\n\n")))
+	}
+	out.WriteString("from __future__ import annotations\n")
+
+	var modules = make([]string, 0, len(r.imports))
+	for module := range r.imports {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	if len(modules) != 0 {
+		out.WriteString("\n")
+	}
+	for _, module := range modules {
+		var names = make([]string, 0, len(r.imports[module]))
+		for name := range r.imports[module] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(&out, "from %s import %s\n", module, strings.Join(names, ", "))
+	}
+
+	out.Write(r.Out.Bytes())
+	return out.Bytes(), nil
+}
+
+type renderer struct {
+	generators.Renderer
+	types   typemap.Table
+	names   naming.Convention
+	options Options
+	imports map[string]map[string]bool
+}
+
+// use records an import of name from module.
+func (r *renderer) use(module string, name string) string {
+	if r.imports[module] == nil {
+		r.imports[module] = map[string]bool{}
+	}
+	r.imports[module][name] = true
+	return name
+}
+
+func (r *renderer) render(definition rewrite.Applicable) {
+	if definition == nil {
+		return
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.CommentDefinition:
+		r.Printf("\n")
+		for _, line := range def.Contents {
+			r.Printf("# %s\n", line)
+		}
+	case rewrite.DataDefinition:
+		r.renderClass(def)
+	case rewrite.DataTypeDefinition:
+		r.Printf("\n%s = %s\n", r.names.Name(naming.Type, def.Name), r.typeName(def.Type))
+		r.docstring("", def.Description)
+	case rewrite.TypeDefinition:
+		r.Printf("\n%s = %s\n", r.names.Name(naming.Type, def.Name), r.typeName(definition))
+		r.docstring("", def.Description)
+	case rewrite.MethodDefinition:
+		r.Printf("\n\n")
+		r.renderFunction("", false, def)
+	case rewrite.VariableDefinition:
+		var name = r.names.Name(naming.Variable, def.Name)
+		if def.Constant {
+			name = r.names.Name(naming.Constant, def.Name)
+		}
+		r.Printf("\n%s", name)
+		if def.Type != nil {
+			r.Printf(": %s", r.typeName(def.Type))
+		}
+		if def.Assign != nil && def.Assign.Value != nil {
+			r.Printf(" = %s", generators.Value(def.Assign.Value, "None"))
+		}
+		r.Printf("\n")
+		r.docstring("", def.Description)
+	}
+}
+
+func (r *renderer) renderClass(def rewrite.DataDefinition) {
+	r.Printf("\n\n")
+	if r.options.Pydantic {
+		r.Printf("class %s(%s):\n", r.names.Name(naming.Type, def.Name), r.use("pydantic", "BaseModel"))
+	} else {
+		r.Printf("@%s\nclass %s:\n", r.use("dataclasses", "dataclass"), r.names.Name(naming.Type, def.Name))
+	}
+
+	if def.Description == "" && len(def.Fields) == 0 && len(def.Methods) == 0 {
+		r.Printf("    pass\n")
+		return
+	}
+
+	r.docstring("    ", def.Description)
+	if def.Description != "" && len(def.Fields) != 0 {
+		r.Printf("\n")
+	}
+	for _, field := range def.Fields {
+		var name = r.names.Name(naming.Field, field.Name)
+		r.Printf("    %s: %s", name, r.typeName(field.Type))
+
+		// renamed fields keep their declared name, as the alias pydantic
+		// validates and serializes with, or in the metadata of dataclass
+		// fields for serializers to read.
+		switch {
+		case name == field.Name:
+		case r.options.Pydantic:
+			r.Printf(" = %s(alias=%q)", r.use("pydantic", "Field"), field.Name)
+		default:
+			r.Printf(" = %s(metadata={\"name\": %q})", r.use("dataclasses", "field"), field.Name)
+		}
+		r.Printf("\n")
+		r.docstring("    ", field.Description)
+	}
+
+	for index, method := range def.Methods {
+		if index != 0 || len(def.Fields) != 0 || def.Description != "" {
+			r.Printf("\n")
+		}
+		r.renderFunction("    ", true, method)
+	}
+}
+
+// renderFunction renders a typed function stub, with a self argument
+// for methods declared within a class.
+func (r *renderer) renderFunction(indent string, method bool, def rewrite.MethodDefinition) {
+	var args []string
+	if method {
+		args = append(args, "self")
+	}
+	for _, arg := range def.Arguments {
+		args = append(args, fmt.Sprintf("%s: %s", r.names.Name(naming.Variable, arg.Name), r.typeName(arg.Type)))
+	}
+
+	r.Printf("%sdef %s(%s) -> %s:\n", indent, r.names.Name(naming.Method, def.Name), strings.Join(args, ", "), r.returnType(def.Returns))
+	r.docstring(indent+"    ", def.Description)
+	r.Printf("%s    raise NotImplementedError\n", indent)
+}
+
+// returnType returns the Python return annotation for a method, where
+// multiple return values are expressed as a tuple.
+func (r *renderer) returnType(returns []rewrite.ReturnDefinition) string {
+	switch len(returns) {
+	case 0:
+		return "None"
+	case 1:
+		return r.typeName(returns[0].Type)
+	}
+
+	var types = make([]string, 0, len(returns))
+	for _, ret := range returns {
+		types = append(types, r.typeName(ret.Type))
+	}
+	return fmt.Sprintf("%s[%s]", r.use("typing", "Tuple"), strings.Join(types, ", "))
+}
+
+// typeName returns the Python type annotation for the giving type definition.
+func (r *renderer) typeName(definition rewrite.Applicable) string {
+	if definition == nil {
+		return r.use("typing", "Any")
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return r.baseType(def)
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return r.typeName(def.Type)
+		}
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.DataDefinition:
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.FutureDefinition:
+		return fmt.Sprintf("%s[%s]", r.use("typing", "Awaitable"), r.typeName(def.Type))
+	case rewrite.StreamDefinition:
+		return fmt.Sprintf("%s[%s]", r.use("typing", "AsyncIterator"), r.typeName(def.Type))
+	case rewrite.ChannelDefinition:
+		return fmt.Sprintf("%s[%s]", r.use("asyncio", "Queue"), r.typeName(def.Type))
+	case rewrite.MethodDefinition:
+		var args = make([]string, 0, len(def.Arguments))
+		for _, arg := range def.Arguments {
+			args = append(args, r.typeName(arg.Type))
+		}
+		return fmt.Sprintf("%s[[%s], %s]", r.use("typing", "Callable"), strings.Join(args, ", "), r.returnType(def.Returns))
+	case rewrite.FieldDefinition:
+		return r.typeName(def.Type)
+	case rewrite.ReturnDefinition:
+		return r.typeName(def.Type)
+	}
+
+	r.SetErr(fmt.Errorf("python: %T is not a type definition", definition.Elem()))
+	return ""
+}
+
+func (r *renderer) baseType(def rewrite.TypeDefinition) string {
+	var typ, ok = r.types.Lookup(def)
+	if !ok {
+		return r.use("typing", "Any")
+	}
+	if typ.Import != "" {
+		return r.use(typ.Import, typ.Name)
+	}
+	return typ.Name
+}
+
+// docstring renders a docstring for a declaration with a description.
+func (r *renderer) docstring(indent string, description string) {
+	if description != "" {
+		r.Printf("%s\n", docstring(indent, description))
+	}
+}
+
+// docText escapes backslashes and closing quotes within docstrings.
+var docText = strings.NewReplacer(`\`, `\\`, `"""`, `\"\"\"`)
+
+// docstring returns text as a docstring indented by indent, where the
+// closing quotes of a docstring of several lines are on a line of their own.
+func docstring(indent string, text string) string {
+	text = docText.Replace(text)
+	if strings.HasSuffix(text, `"`) {
+		text = text[:len(text)-1] + `\"`
+	}
+
+	var lines = strings.Split(text, "\n")
+	if len(lines) == 1 {
+		return indent + `"""` + text + `"""`
+	}
+	for index := range lines[1:] {
+		if lines[index+1] != "" {
+			lines[index+1] = indent + lines[index+1]
+		}
+	}
+	return indent + `"""` + strings.Join(lines, "\n") + "\n" + indent + `"""`
+}
@@ -0,0 +1,131 @@
+package python_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/python"
+	"github.com/stretchr/testify/require"
+)
+
+func userPackage() rewrite.PackageDefinition {
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "A registered user."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "joined"}, Type: &rewrite.TypeDefinition{Type: rewrite.Time}},
+		},
+		Methods: []rewrite.MethodDefinition{
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "friends"},
+				Returns: []rewrite.ReturnDefinition{
+					{Type: &rewrite.StreamDefinition{Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "User"}}}},
+				},
+			},
+		},
+	}
+
+	return rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models", Description: "User models."},
+		Definitions: []rewrite.Applicable{
+			user,
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "fetch_user"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+				},
+				Returns: []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: user}}},
+			},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	var code, err = python.Render(userPackage(), python.Options{})
+	require.NoError(t, err)
+	require.Equal(t, `"""User models."""
+
+from __future__ import annotations
+
+from dataclasses import dataclass
+from datetime import datetime
+from typing import AsyncIterator, Awaitable
+
+
+@dataclass
+class User:
+    """A registered user."""
+
+    name: str
+    joined: datetime
+
+    def friends(self) -> AsyncIterator[User]:
+        raise NotImplementedError
+
+
+def fetch_user(id: int) -> Awaitable[User]:
+    raise NotImplementedError
+`, string(code))
+}
+
+func TestRenderPydantic(t *testing.T) {
+	var code, err = python.Render(userPackage(), python.Options{Pydantic: true})
+	require.NoError(t, err)
+	require.Contains(t, string(code), "from pydantic import BaseModel\n")
+	require.Contains(t, string(code), "class User(BaseModel):\n")
+	require.NotContains(t, string(code), "dataclass")
+}
+
+func TestRenderRenamed(t *testing.T) {
+	var email = &rewrite.DataTypeDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "email_address"},
+		Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "accounts"},
+		Definitions: []rewrite.Applicable{
+			email,
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "account"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "emailAddress"}, Type: email},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "active"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+			},
+		},
+	}
+
+	var out, err = python.Render(pkg, python.Options{})
+	require.NoError(t, err)
+	var code = string(out)
+	require.Contains(t, code, "EmailAddress = str\n")
+	require.Contains(t, code, "    email_address: EmailAddress = field(metadata={\"name\": \"emailAddress\"})\n    active: str\n")
+	require.Contains(t, code, "from dataclasses import dataclass, field\n")
+}
+
+func TestRenderKeywords(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "search"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "find"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "for"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+				Returns: []rewrite.ReturnDefinition{{Type: &rewrite.TypeDefinition{Type: rewrite.String}}},
+			},
+			&rewrite.VariableDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "if"},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+				Assign:         &rewrite.AssignmentDefinition{Value: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "\"any\""}}},
+			},
+		},
+	}
+
+	var out, err = python.Render(pkg, python.Options{})
+	require.NoError(t, err)
+	var code = string(out)
+	require.Contains(t, code, "def find(for_: str) -> str:")
+	require.Contains(t, code, "if_: str = \"any\"")
+}
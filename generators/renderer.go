@@ -0,0 +1,109 @@
+package generators
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/influx6/rewrite"
+)
+
+// Renderer holds the rendered output of a backend and the first error met
+// while rendering it, backends embed it into their own renderer.
+type Renderer struct {
+	// Out holds the rendered output.
+	Out bytes.Buffer
+
+	// Err holds the first error met while rendering.
+	Err error
+
+	// DocFormat formats a description of a single line as a doc comment
+	// of the target, e.g "/** %s */" or "/// %s".
+	DocFormat string
+
+	// DocBlock holds the opening, line prefix and closing of a doc comment
+	// spanning several lines, e.g {"/**", " * ", " */"}. Descriptions of
+	// several lines format every line with DocFormat when it is empty.
+	DocBlock [3]string
+
+	// DocEscape escapes text which would end a doc comment early, e.g the
+	// */ of block comments, descriptions are written as is when it is nil.
+	DocEscape func(string) string
+}
+
+// SetErr records err unless an error was already recorded.
+func (r *Renderer) SetErr(err error) {
+	if r.Err == nil {
+		r.Err = err
+	}
+}
+
+// Printf writes formatted text to the output.
+func (r *Renderer) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(&r.Out, format, args...)
+}
+
+// Doc writes a description as a doc comment indented by indent, where
+// declarations without indent are separated from the previous one by an
+// empty line. Nothing but the separator is written for empty descriptions.
+func (r *Renderer) Doc(indent string, description string) {
+	if indent == "" {
+		r.Printf("\n")
+	}
+	if description == "" {
+		return
+	}
+	if r.DocEscape != nil {
+		description = r.DocEscape(description)
+	}
+
+	var lines = strings.Split(description, "\n")
+	if len(lines) == 1 || r.DocBlock[0] == "" {
+		for _, line := range lines {
+			r.Printf("%s\n", strings.TrimRight(indent+fmt.Sprintf(r.DocFormat, line), " "))
+		}
+		return
+	}
+
+	r.Printf("%s%s\n", indent, r.DocBlock[0])
+	for _, line := range lines {
+		r.Printf("%s\n", strings.TrimRight(indent+r.DocBlock[1]+line, " "))
+	}
+	r.Printf("%s%s\n", indent, r.DocBlock[2])
+}
+
+// Comment formats every line of text with format, e.g "// %s", for
+// comments of the target which end with their line.
+func Comment(format string, text string) string {
+	var lines = strings.Split(text, "\n")
+	for index, line := range lines {
+		lines[index] = strings.TrimRight(fmt.Sprintf(format, line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// EscapeCommentEnd escapes the */ ending C style block comments in text.
+func EscapeCommentEnd(text string) string {
+	return strings.Replace(text, "*/", "*\\/", -1)
+}
+
+// Annotation returns the text of an annotation, e.g "@deprecated use Find".
+func Annotation(def rewrite.AnnotationDefinition) string {
+	return strings.TrimSpace(fmt.Sprintf("@%s %s", def.Name, def.Content))
+}
+
+// Value returns the literal text of a value definition, or the name of
+// other named definitions. Definitions without a text return null, the
+// null literal of the target.
+func Value(definition rewrite.Applicable, null string) string {
+	if def, ok := definition.Elem().(rewrite.Value); ok {
+		if def.Value != nil {
+			return Value(def.Value, null)
+		}
+		return def.Name
+	}
+	if named, ok := definition.(interface{ GetName() string }); ok {
+		return named.GetName()
+	}
+	return null
+}
@@ -0,0 +1,60 @@
+package generators_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer(t *testing.T) {
+	var r = generators.Renderer{DocFormat: "/** %s */"}
+	r.Doc("", "User is a registered user.")
+	r.Printf("class %s {\n", "User")
+	r.Doc("  ", "")
+	r.Doc("  ", "Name is the display name.")
+	r.Printf("}\n")
+	require.Equal(t, "\n/** User is a registered user. */\nclass User {\n  /** Name is the display name. */\n}\n", r.Out.String())
+
+	var first = errors.New("first")
+	r.SetErr(first)
+	r.SetErr(errors.New("second"))
+	require.Equal(t, first, r.Err)
+}
+
+func TestRendererDocLines(t *testing.T) {
+	var r = generators.Renderer{
+		DocFormat: "/** %s */",
+		DocBlock:  [3]string{"/**", " * ", " */"},
+		DocEscape: generators.EscapeCommentEnd,
+	}
+	r.Doc("  ", "Pattern matches */ paths.\n\nIt is a glob.")
+	r.Doc("  ", "Ends a comment with */.")
+	require.Equal(t, "  /**\n   * Pattern matches *\\/ paths.\n   *\n   * It is a glob.\n   */\n  /** Ends a comment with *\\/. */\n", r.Out.String())
+
+	r = generators.Renderer{DocFormat: "/// %s"}
+	r.Doc("\t", "First line.\nSecond line.")
+	require.Equal(t, "\t/// First line.\n\t/// Second line.\n", r.Out.String())
+	require.Equal(t, "// First line.\n//\n// Third line.", generators.Comment("// %s", "First line.\n\nThird line."))
+}
+
+func TestAnnotation(t *testing.T) {
+	require.Equal(t, "@deprecated use Find", generators.Annotation(rewrite.AnnotationDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "deprecated"}, Content: "use Find"}))
+	require.Equal(t, "@internal", generators.Annotation(rewrite.AnnotationDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "internal"}}))
+}
+
+func TestValue(t *testing.T) {
+	require.Equal(t, "10", generators.Value(&rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "10"}}, "null"))
+	require.Equal(t, "10", generators.Value(&rewrite.Value{Value: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "10"}}}, "null"))
+	require.Equal(t, "limit", generators.Value(&rewrite.VariableDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "limit"}}, "null"))
+	require.Equal(t, "null", generators.Value(unnamed{}, "null"))
+}
+
+// unnamed is a definition without a name.
+type unnamed struct{}
+
+func (unnamed) Elem() interface{} { return unnamed{} }
+
+func (unnamed) Apply(interface{}) error { return rewrite.ErrNotApplicable }
@@ -0,0 +1,372 @@
+// Package rust renders a rewrite.PackageDefinition into a Rust module.
+package rust
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/naming"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// DeriveAnnotation adds the comma separated traits in its content to the
+// derive list of a DataDefinition, e.g "Serialize, Deserialize". The serde
+// traits Serialize and Deserialize are imported when derived.
+const DeriveAnnotation = "derive"
+
+// Options configures how definitions are rendered.
+type Options struct {
+	// Derives lists the traits derived by every struct, defaults to
+	// Debug, Clone and PartialEq. Structs with channel, future or stream
+	// fields only derive those of the traits their fields implement.
+	Derives []string
+}
+
+var defaultDerives = []string{"Debug", "Clone", "PartialEq"}
+
+func init() {
+	typemap.Defaults("rust", typemap.Table{
+		{Type: rewrite.String}:                         {Name: "String"},
+		{Type: rewrite.Rune}:                           {Name: "char"},
+		{Type: rewrite.Integer}:                        {Name: "i64"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "i32"},
+		{Type: rewrite.Decimal}:                        {Name: "f64"},
+		{Type: rewrite.Decimal, Memory: rewrite.Bit32}: {Name: "f32"},
+		{Type: rewrite.Complex}:                        {Name: "Complex<f64>", Import: "num_complex::Complex"},
+		{Type: rewrite.Complex, Memory: rewrite.Bit32}: {Name: "Complex<f32>", Import: "num_complex::Complex"},
+		{Type: rewrite.Time}:                           {Name: "SystemTime", Import: "std::time::SystemTime"},
+	})
+	naming.Defaults("rust", naming.Convention{
+		Cases: map[naming.Construct]naming.Case{
+			naming.Type:     naming.Pascal,
+			naming.Field:    naming.Snake,
+			naming.Method:   naming.Snake,
+			naming.Constant: naming.Screaming,
+			naming.Variable: naming.Snake,
+		},
+		Keywords: naming.Set(
+			"as", "async", "await", "break", "const", "continue", "crate",
+			"dyn", "else", "enum", "extern", "false", "fn", "for", "if", "impl",
+			"in", "let", "loop", "match", "mod", "move", "mut", "pub", "ref",
+			"return", "self", "Self", "static", "struct", "super", "trait",
+			"true", "type", "unsafe", "use", "where", "while",
+		),
+		Escape: func(name string) string {
+			switch name {
+			case "crate", "self", "Self", "super":
+				// these can not be raw identifiers.
+				return name + "_"
+			}
+			return "r#" + name
+		},
+	})
+	generators.Register(generators.Single("rust", ".rs", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg, Options{})
+	}))
+}
+
+// Render returns the Rust source for the giving package definition.
+//
+// DataDefinition are rendered as structs with their methods in an impl
+// block, methods returning a FutureDefinition are rendered as async
+// functions. Statements found directly within the package are ignored.
+func Render(pkg rewrite.PackageDefinition, options Options) ([]byte, error) {
+	if options.Derives == nil {
+		options.Derives = defaultDerives
+	}
+
+	var r = renderer{Renderer: generators.Renderer{DocFormat: "/// %s"}, options: options, imports: map[string]bool{}, types: typemap.For("rust"), names: naming.For("rust")}
+	for _, definition := range pkg.Definitions {
+		r.render(definition)
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	var out bytes.Buffer
+	if pkg.Description != "" {
+		fmt.Fprintf(&out, "%s\n", generators.Comment("//! %s", pkg.Description))
+	}
+	if pkg.Version != "" {
+		fmt.Fprintf(&out, "//! Version: %s\n", pkg.Version)
+	}
+
+	var imports = make([]string, 0, len(r.imports))
+	for path := range r.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	if len(imports) != 0 {
+		out.WriteString("\n")
+	}
+	for _, path := range imports {
+		fmt.Fprintf(&out, "use %s;\n", path)
+	}
+
+	out.Write(r.Out.Bytes())
+	return out.Bytes(), nil
+}
+
+type renderer struct {
+	generators.Renderer
+	types   typemap.Table
+	names   naming.Convention
+	options Options
+	imports map[string]bool
+}
+
+func (r *renderer) render(definition rewrite.Applicable) {
+	if definition == nil {
+		return
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.CommentDefinition:
+		r.Printf("\n")
+		for _, line := range def.Contents {
+			r.Printf("// %s\n", line)
+		}
+	case rewrite.DataDefinition:
+		r.renderData(def)
+	case rewrite.DataTypeDefinition:
+		r.Doc("", def.Description)
+		r.Printf("pub type %s = %s;\n", r.names.Name(naming.Type, def.Name), r.typeName(def.Type))
+	case rewrite.TypeDefinition:
+		r.Doc("", def.Description)
+		r.Printf("pub type %s = %s;\n", r.names.Name(naming.Type, def.Name), r.typeName(definition))
+	case rewrite.MethodDefinition:
+		r.Doc("", def.Description)
+		r.renderMethod("", false, def)
+	case rewrite.VariableDefinition:
+		r.renderVariable(def)
+	}
+}
+
+func (r *renderer) renderData(def rewrite.DataDefinition) {
+	// traits holds the traits implemented by the channel, future and
+	// stream fields of the struct, nil if it has none.
+	var traits map[string]bool
+	for _, field := range def.Fields {
+		if implemented, ok := runtimeTraits(field.Type); ok {
+			if traits == nil {
+				traits = implemented
+				continue
+			}
+			for trait := range traits {
+				if !implemented[trait] {
+					delete(traits, trait)
+				}
+			}
+		}
+	}
+
+	var derives []string
+	for _, derive := range r.options.Derives {
+		if traits == nil || traits[derive] {
+			derives = append(derives, derive)
+		}
+	}
+	if annotation, ok := def.Annotations.Get(DeriveAnnotation); ok {
+		for _, derive := range strings.Split(annotation.Content, ",") {
+			if derive = strings.TrimSpace(derive); derive == "" {
+				continue
+			}
+			if traits != nil && !traits[derive] {
+				r.SetErr(fmt.Errorf("rust: %s can not derive %s, its channel, future or stream fields do not implement it", def.Name, derive))
+				continue
+			}
+			derives = append(derives, derive)
+		}
+	}
+	for _, derive := range derives {
+		if derive == "Serialize" || derive == "Deserialize" {
+			r.imports["serde::"+derive] = true
+		}
+	}
+
+	r.Doc("", def.Description)
+	if len(derives) != 0 {
+		r.Printf("#[derive(%s)]\n", strings.Join(derives, ", "))
+	}
+	r.Printf("pub struct %s {\n", r.names.Name(naming.Type, def.Name))
+	for _, field := range def.Fields {
+		var name = r.names.Name(naming.Field, field.Name)
+		r.Doc("    ", field.Description)
+		if name != field.Name && serde(derives) {
+			r.Printf("    #[serde(rename = %q)]\n", field.Name)
+		}
+		r.Printf("    pub %s: %s,\n", name, r.typeName(field.Type))
+	}
+	r.Printf("}\n")
+
+	if len(def.Methods) == 0 {
+		return
+	}
+
+	r.Printf("\nimpl %s {\n", r.names.Name(naming.Type, def.Name))
+	for index, method := range def.Methods {
+		if index != 0 {
+			r.Printf("\n")
+		}
+		r.Doc("    ", method.Description)
+		r.renderMethod("    ", true, method)
+	}
+	r.Printf("}\n")
+}
+
+// renderMethod renders a function, with a self receiver for methods
+// declared within an impl block.
+func (r *renderer) renderMethod(indent string, receiver bool, def rewrite.MethodDefinition) {
+	var returns = def.Returns
+	var async = len(returns) == 1 && isFuture(returns[0].Type)
+
+	var args []string
+	if receiver {
+		args = append(args, "&self")
+	}
+	for _, arg := range def.Arguments {
+		args = append(args, fmt.Sprintf("%s: %s", r.names.Name(naming.Variable, arg.Name), r.typeName(arg.Type)))
+	}
+
+	r.Printf("%spub ", indent)
+	if async {
+		r.Printf("async ")
+	}
+	r.Printf("fn %s(%s)", r.names.Name(naming.Method, def.Name), strings.Join(args, ", "))
+
+	switch {
+	case async:
+		var future = returns[0].Type.Elem().(rewrite.FutureDefinition)
+		r.Printf(" -> %s", r.typeName(future.Type))
+	case len(returns) == 1:
+		r.Printf(" -> %s", r.typeName(returns[0].Type))
+	case len(returns) > 1:
+		var types = make([]string, 0, len(returns))
+		for _, ret := range returns {
+			types = append(types, r.typeName(ret.Type))
+		}
+		r.Printf(" -> (%s)", strings.Join(types, ", "))
+	}
+
+	r.Printf(" {\n%s    unimplemented!()\n%s}\n", indent, indent)
+}
+
+func (r *renderer) renderVariable(def rewrite.VariableDefinition) {
+	if def.Type == nil || def.Assign == nil || def.Assign.Value == nil {
+		r.SetErr(fmt.Errorf("rust: variable %q requires a type and value", def.Name))
+		return
+	}
+
+	var keyword = "static"
+	if def.Constant {
+		keyword = "const"
+	}
+
+	// statics are named like constants in Rust.
+	r.Doc("", def.Description)
+	r.Printf("pub %s %s: %s = %s;\n", keyword, r.names.Name(naming.Constant, def.Name), r.typeName(def.Type), generators.Value(def.Assign.Value, ""))
+}
+
+// typeName returns the Rust type for the giving type definition.
+func (r *renderer) typeName(definition rewrite.Applicable) string {
+	if definition == nil {
+		r.SetErr(fmt.Errorf("rust: missing type definition"))
+		return ""
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return r.baseType(def)
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return r.typeName(def.Type)
+		}
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.DataDefinition:
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.ChannelDefinition:
+		r.imports["tokio::sync::mpsc"] = true
+		var elem = r.typeName(def.Type)
+		switch def.Direction {
+		case rewrite.IncomingDirectional:
+			return fmt.Sprintf("mpsc::Receiver<%s>", elem)
+		case rewrite.OutgoingDirectional:
+			return fmt.Sprintf("mpsc::Sender<%s>", elem)
+		default:
+			return fmt.Sprintf("(mpsc::Sender<%s>, mpsc::Receiver<%s>)", elem, elem)
+		}
+	case rewrite.FutureDefinition:
+		r.imports["std::future::Future"] = true
+		r.imports["std::pin::Pin"] = true
+		return fmt.Sprintf("Pin<Box<dyn Future<Output = %s> + Send>>", r.typeName(def.Type))
+	case rewrite.StreamDefinition:
+		r.imports["futures::Stream"] = true
+		r.imports["std::pin::Pin"] = true
+		return fmt.Sprintf("Pin<Box<dyn Stream<Item = %s> + Send>>", r.typeName(def.Type))
+	case rewrite.FieldDefinition:
+		return r.typeName(def.Type)
+	case rewrite.ReturnDefinition:
+		return r.typeName(def.Type)
+	}
+
+	r.SetErr(fmt.Errorf("rust: %T can not be expressed as a type", definition.Elem()))
+	return ""
+}
+
+func (r *renderer) baseType(def rewrite.TypeDefinition) string {
+	var typ, ok = r.types.Lookup(def)
+	if !ok {
+		r.SetErr(fmt.Errorf("rust: unknown base type %q", def.Type))
+		return ""
+	}
+	if typ.Import != "" {
+		r.imports[typ.Import] = true
+	}
+	return typ.Name
+}
+
+// serde returns true if the derives include a serde trait, which reads
+// the rename attribute of fields.
+func serde(derives []string) bool {
+	for _, derive := range derives {
+		switch derive {
+		case "Serialize", "Deserialize", "serde::Serialize", "serde::Deserialize":
+			return true
+		}
+	}
+	return false
+}
+
+// runtimeTraits returns the derivable traits implemented by a channel,
+// future or stream type, it returns false if the type is none of them.
+func runtimeTraits(definition rewrite.Applicable) (map[string]bool, bool) {
+	if definition == nil {
+		return nil, false
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.ChannelDefinition:
+		if def.Direction == rewrite.OutgoingDirectional {
+			return map[string]bool{"Debug": true, "Clone": true}, true
+		}
+		return map[string]bool{"Debug": true}, true
+	case rewrite.FutureDefinition, rewrite.StreamDefinition:
+		return map[string]bool{}, true
+	case rewrite.DataTypeDefinition:
+		return runtimeTraits(def.Type)
+	}
+	return nil, false
+}
+
+func isFuture(definition rewrite.Applicable) bool {
+	if definition == nil {
+		return false
+	}
+	var _, ok = definition.Elem().(rewrite.FutureDefinition)
+	return ok
+}
@@ -0,0 +1,134 @@
+package rust_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/rust"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "A registered user."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "age"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "score"}, Type: &rewrite.TypeDefinition{Type: rewrite.Decimal}},
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "events"},
+				Type:           &rewrite.ChannelDefinition{Direction: rewrite.OutgoingDirectional, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			},
+		},
+		Methods: []rewrite.MethodDefinition{
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "reload"},
+				Returns: []rewrite.ReturnDefinition{
+					{Type: &rewrite.FutureDefinition{Type: &rewrite.TypeDefinition{Type: rewrite.Integer}}},
+				},
+			},
+		},
+	}
+
+	var profile = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "Profile"},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "bio"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+		},
+		Annotations: rewrite.Annotations{
+			{BaseDefinition: rewrite.BaseDefinition{Name: rust.DeriveAnnotation}, Content: "Serialize, Deserialize"},
+		},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions: []rewrite.Applicable{
+			user,
+			profile,
+			&rewrite.VariableDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "LIMIT"},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32},
+				Assign:         &rewrite.AssignmentDefinition{Value: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "10"}}},
+				Constant:       true,
+			},
+		},
+	}
+
+	var code, err = rust.Render(pkg, rust.Options{})
+	require.NoError(t, err)
+	require.Equal(t, `
+use serde::Deserialize;
+use serde::Serialize;
+use tokio::sync::mpsc;
+
+/// A registered user.
+#[derive(Debug, Clone)]
+pub struct User {
+    pub age: i32,
+    pub score: f64,
+    pub events: mpsc::Sender<String>,
+}
+
+impl User {
+    pub async fn reload(&self) -> i64 {
+        unimplemented!()
+    }
+}
+
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct Profile {
+    pub bio: String,
+}
+
+pub const LIMIT: i32 = 10;
+`, string(code))
+}
+
+func TestRenderDeriveRuntimeField(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Feed"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "items"}, Type: &rewrite.StreamDefinition{Type: &rewrite.TypeDefinition{Type: rewrite.String}}},
+				},
+			},
+		},
+	}
+
+	var code, err = rust.Render(pkg, rust.Options{})
+	require.NoError(t, err)
+	require.Contains(t, string(code), "pub struct Feed {")
+	require.NotContains(t, string(code), "derive")
+
+	pkg.Definitions[0].(*rewrite.DataDefinition).Annotations = rewrite.Annotations{
+		{BaseDefinition: rewrite.BaseDefinition{Name: rust.DeriveAnnotation}, Content: "Serialize"},
+	}
+	_, err = rust.Render(pkg, rust.Options{})
+	require.Error(t, err)
+}
+
+func TestRenderKeywords(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "search"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "find"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "for"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+				Returns: []rewrite.ReturnDefinition{{Type: &rewrite.TypeDefinition{Type: rewrite.String}}},
+			},
+			&rewrite.VariableDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "if"},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+				Assign:         &rewrite.AssignmentDefinition{Value: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "\"any\""}}},
+			},
+		},
+	}
+
+	var out, err = rust.Render(pkg, rust.Options{})
+	require.NoError(t, err)
+	var code = string(out)
+	require.Contains(t, code, "pub fn find(r#for: String) -> String {")
+}
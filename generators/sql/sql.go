@@ -0,0 +1,293 @@
+// Package sql renders the data definitions of a rewrite.PackageDefinition
+// into CREATE TABLE statements for a SQL dialect.
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Annotations understood by the sql backend.
+const (
+	// TableAnnotation sets the table name of a DataDefinition to its
+	// content, the definition name is used otherwise.
+	TableAnnotation = "table"
+
+	// PrimaryKeyAnnotation marks a FieldDefinition as part of the primary
+	// key, multiple fields form a composite key.
+	PrimaryKeyAnnotation = "primary_key"
+
+	// IndexAnnotation creates an index on a FieldDefinition, named by its
+	// content or after the table and column if empty.
+	IndexAnnotation = "index"
+
+	// UniqueAnnotation adds a unique constraint to a FieldDefinition.
+	UniqueAnnotation = "unique"
+
+	// NullableAnnotation allows a FieldDefinition to be null, all other
+	// columns are NOT NULL.
+	NullableAnnotation = "nullable"
+)
+
+// Dialect defines the SQL dialect statements are rendered in.
+type Dialect int
+
+const (
+	PostgreSQL Dialect = iota
+	MySQL
+	SQLite
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case PostgreSQL:
+		return "postgresql"
+	case MySQL:
+		return "mysql"
+	case SQLite:
+		return "sqlite"
+	default:
+		return "unknown"
+	}
+}
+
+// Generator returns the name the type mappings of the dialect are declared
+// under in the typemap package, e.g "sql/postgresql".
+func (d Dialect) Generator() string {
+	return "sql/" + d.String()
+}
+
+// Options configures how definitions are rendered.
+type Options struct {
+	Dialect Dialect
+}
+
+func init() {
+	typemap.Defaults(PostgreSQL.Generator(), typemap.Table{
+		{Type: rewrite.String}:                         {Name: "TEXT"},
+		{Type: rewrite.Rune}:                           {Name: "CHAR(1)"},
+		{Type: rewrite.Integer}:                        {Name: "BIGINT"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "INTEGER"},
+		{Type: rewrite.Decimal}:                        {Name: "DOUBLE PRECISION"},
+		{Type: rewrite.Decimal, Memory: rewrite.Bit32}: {Name: "REAL"},
+		{Type: rewrite.Time}:                           {Name: "TIMESTAMP WITH TIME ZONE"},
+	})
+	typemap.Defaults(MySQL.Generator(), typemap.Table{
+		{Type: rewrite.String}:                         {Name: "TEXT"},
+		{Type: rewrite.Rune}:                           {Name: "CHAR(1)"},
+		{Type: rewrite.Integer}:                        {Name: "BIGINT"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "INT"},
+		{Type: rewrite.Decimal}:                        {Name: "DOUBLE"},
+		{Type: rewrite.Decimal, Memory: rewrite.Bit32}: {Name: "FLOAT"},
+		{Type: rewrite.Time}:                           {Name: "DATETIME(6)"},
+	})
+	typemap.Defaults(SQLite.Generator(), typemap.Table{
+		{Type: rewrite.String}:  {Name: "TEXT"},
+		{Type: rewrite.Rune}:    {Name: "TEXT"},
+		{Type: rewrite.Integer}: {Name: "INTEGER"},
+		{Type: rewrite.Decimal}: {Name: "REAL"},
+		{Type: rewrite.Time}:    {Name: "TEXT"},
+	})
+	generators.Register(generators.Single("sql", ".sql", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg, Options{})
+	}))
+
+	// the other dialects are generated by their own name, as "sql" renders
+	// the default dialect.
+	for _, dialect := range []Dialect{MySQL, SQLite} {
+		var dialect = dialect
+		generators.Register(generators.Single(dialect.String(), ".sql", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+			return Render(pkg, Options{Dialect: dialect})
+		}))
+	}
+}
+
+// Render returns the CREATE TABLE and CREATE INDEX statements for every
+// DataDefinition with fields in the giving package definition.
+//
+// Fields whose type is a DataDefinition are stored as JSON columns, while
+// futures, streams and channels can not be stored and fail rendering.
+// References to a type by name alone, a DataTypeDefinition without a type,
+// resolve to the definition of that name declared in the package.
+func Render(pkg rewrite.PackageDefinition, options Options) ([]byte, error) {
+	var r = renderer{
+		dialect:   options.Dialect,
+		types:     typemap.For(options.Dialect.Generator()),
+		declared:  map[string]rewrite.Applicable{},
+		resolving: map[string]bool{},
+	}
+	for _, definition := range pkg.Definitions {
+		if definition == nil {
+			continue
+		}
+		switch def := definition.Elem().(type) {
+		case rewrite.DataDefinition:
+			r.declared[def.Name] = definition
+		case rewrite.DataTypeDefinition:
+			if def.Type != nil {
+				r.declared[def.Name] = definition
+			}
+		case rewrite.TypeDefinition:
+			r.declared[def.Name] = definition
+		}
+	}
+
+	if pkg.Description != "" {
+		r.Printf("%s\n", generators.Comment("-- %s", pkg.Description))
+	}
+	if pkg.Version != "" {
+		r.Printf("-- Version: %s\n", pkg.Version)
+	}
+
+	for _, definition := range pkg.Definitions {
+		if definition == nil {
+			continue
+		}
+		if data, ok := definition.Elem().(rewrite.DataDefinition); ok && len(data.Fields) != 0 {
+			r.renderTable(data)
+		}
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return r.Out.Bytes(), nil
+}
+
+type renderer struct {
+	generators.Renderer
+	types   typemap.Table
+	dialect Dialect
+
+	// declared holds the named definitions of the package, which
+	// references resolve to, and resolving those being resolved.
+	declared  map[string]rewrite.Applicable
+	resolving map[string]bool
+}
+
+func (r *renderer) renderTable(def rewrite.DataDefinition) {
+	var table = def.Name
+	if annotation, ok := def.Annotations.Get(TableAnnotation); ok && annotation.Content != "" {
+		table = annotation.Content
+	}
+
+	var lines []string
+	var keys []string
+	var indexes []string
+	for _, field := range def.Fields {
+		var keyed = field.Annotations.Has(PrimaryKeyAnnotation) ||
+			field.Annotations.Has(IndexAnnotation) ||
+			field.Annotations.Has(UniqueAnnotation)
+
+		var column = fmt.Sprintf("%s %s", r.quote(field.Name), r.columnType(field.Type, keyed))
+		if !field.Annotations.Has(NullableAnnotation) {
+			column += " NOT NULL"
+		}
+		if field.Annotations.Has(UniqueAnnotation) {
+			column += " UNIQUE"
+		}
+		lines = append(lines, column)
+
+		if field.Annotations.Has(PrimaryKeyAnnotation) {
+			keys = append(keys, r.quote(field.Name))
+		}
+
+		if annotation, ok := field.Annotations.Get(IndexAnnotation); ok {
+			var name = annotation.Content
+			if name == "" {
+				name = fmt.Sprintf("%s_%s_idx", table, field.Name)
+			}
+			indexes = append(indexes, fmt.Sprintf("CREATE INDEX %s ON %s (%s);", r.quote(name), r.quote(table), r.quote(field.Name)))
+		}
+	}
+
+	if len(keys) != 0 {
+		lines = append(lines, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(keys, ", ")))
+	}
+
+	r.Printf("\n")
+	if def.Description != "" {
+		r.Printf("%s\n", generators.Comment("-- %s", def.Description))
+	}
+	r.Printf("CREATE TABLE %s (\n\t%s\n);\n", r.quote(table), strings.Join(lines, ",\n\t"))
+	for _, index := range indexes {
+		r.Printf("\n%s\n", index)
+	}
+}
+
+// quote returns the quoted identifier for the dialect.
+func (r *renderer) quote(name string) string {
+	if r.dialect == MySQL {
+		return "`" + strings.Replace(name, "`", "``", -1) + "`"
+	}
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// columnType returns the column type for the giving type definition, keyed
+// columns use a bounded string type where the dialect can not index text.
+func (r *renderer) columnType(definition rewrite.Applicable, keyed bool) string {
+	if definition == nil {
+		r.SetErr(fmt.Errorf("sql: missing type definition"))
+		return ""
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return r.baseType(def, keyed)
+	case rewrite.DataTypeDefinition:
+		if def.Type == nil {
+			var declared, ok = r.declared[def.Name]
+			if !ok {
+				r.SetErr(fmt.Errorf("sql: type %q is not declared in the package", def.Name))
+				return ""
+			}
+			if r.resolving[def.Name] {
+				r.SetErr(fmt.Errorf("sql: type %q refers to itself", def.Name))
+				return ""
+			}
+			r.resolving[def.Name] = true
+			defer delete(r.resolving, def.Name)
+			return r.columnType(declared, keyed)
+		}
+		return r.columnType(def.Type, keyed)
+	case rewrite.DataDefinition:
+		switch r.dialect {
+		case PostgreSQL:
+			return "JSONB"
+		case MySQL:
+			return "JSON"
+		default:
+			return "TEXT"
+		}
+	case rewrite.FieldDefinition:
+		return r.columnType(def.Type, keyed)
+	}
+
+	r.SetErr(fmt.Errorf("sql: %T can not be stored as a column", definition.Elem()))
+	return ""
+}
+
+func (r *renderer) baseType(def rewrite.TypeDefinition, keyed bool) string {
+	switch r.dialect {
+	case PostgreSQL, MySQL, SQLite:
+	default:
+		r.SetErr(fmt.Errorf("sql: unknown dialect %d", r.dialect))
+		return ""
+	}
+
+	var typ, ok = r.types.Lookup(def)
+	if !ok {
+		r.SetErr(fmt.Errorf("sql: base type %q has no %s column type", def.Type, r.dialect))
+		return ""
+	}
+
+	// MySQL can not index TEXT columns without a prefix length.
+	if keyed && r.dialect == MySQL && typ.Name == "TEXT" {
+		return "VARCHAR(255)"
+	}
+	return typ.Name
+}
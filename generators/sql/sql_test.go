@@ -0,0 +1,120 @@
+package sql_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func annotate(names ...string) rewrite.Annotations {
+	var annotations rewrite.Annotations
+	for _, name := range names {
+		annotations = append(annotations, rewrite.AnnotationDefinition{BaseDefinition: rewrite.BaseDefinition{Name: name}})
+	}
+	return annotations
+}
+
+func userPackage() rewrite.PackageDefinition {
+	return rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "Registered users."},
+				Annotations: rewrite.Annotations{
+					{BaseDefinition: rewrite.BaseDefinition{Name: sql.TableAnnotation}, Content: "users"},
+				},
+				Fields: []rewrite.FieldDefinition{
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "id"},
+						Type:           &rewrite.TypeDefinition{Type: rewrite.Integer},
+						Annotations:    annotate(sql.PrimaryKeyAnnotation),
+					},
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "email"},
+						Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+						Annotations:    annotate(sql.UniqueAnnotation, sql.IndexAnnotation),
+					},
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "deleted"},
+						Type:           &rewrite.TypeDefinition{Type: rewrite.Time},
+						Annotations:    annotate(sql.NullableAnnotation),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	var ddl, err = sql.Render(userPackage(), sql.Options{Dialect: sql.PostgreSQL})
+	require.NoError(t, err)
+	require.Equal(t, `
+-- Registered users.
+CREATE TABLE "users" (
+	"id" BIGINT NOT NULL,
+	"email" TEXT NOT NULL UNIQUE,
+	"deleted" TIMESTAMP WITH TIME ZONE,
+	PRIMARY KEY ("id")
+);
+
+CREATE INDEX "users_email_idx" ON "users" ("email");
+`, string(ddl))
+
+	ddl, err = sql.Render(userPackage(), sql.Options{Dialect: sql.MySQL})
+	require.NoError(t, err)
+	require.Contains(t, string(ddl), "CREATE TABLE `users` (\n\t`id` BIGINT NOT NULL,\n\t`email` VARCHAR(255) NOT NULL UNIQUE,\n\t`deleted` DATETIME(6),\n")
+
+	ddl, err = sql.Render(userPackage(), sql.Options{Dialect: sql.SQLite})
+	require.NoError(t, err)
+	require.Contains(t, string(ddl), "\t\"deleted\" TEXT,\n")
+}
+
+func TestRenderUnstorableType(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "User"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "updates"}, Type: &rewrite.ChannelDefinition{}},
+				},
+			},
+		},
+	}
+
+	var _, err = sql.Render(pkg, sql.Options{})
+	require.Error(t, err)
+}
+
+func TestRenderReferences(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataTypeDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "email_address"},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+			},
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Profile"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "bio"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+			},
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "users"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "email"}, Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "email_address"}}},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "profile"}, Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "Profile"}}},
+				},
+			},
+		},
+	}
+
+	var ddl, err = sql.Render(pkg, sql.Options{})
+	require.NoError(t, err)
+	require.Contains(t, string(ddl), "\t\"email\" TEXT NOT NULL,\n\t\"profile\" JSONB NOT NULL\n")
+
+	pkg.Definitions = pkg.Definitions[2:]
+	_, err = sql.Render(pkg, sql.Options{})
+	require.EqualError(t, err, `sql: type "email_address" is not declared in the package`)
+}
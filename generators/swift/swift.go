@@ -0,0 +1,325 @@
+// Package swift renders a rewrite.PackageDefinition into Swift Codable
+// structs and protocols for iOS clients.
+package swift
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/naming"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+func init() {
+	typemap.Defaults("swift", typemap.Table{
+		// Character is not Codable, so runes are encoded as strings.
+		{Type: rewrite.Rune}:                           {Name: "String"},
+		{Type: rewrite.String}:                         {Name: "String"},
+		{Type: rewrite.Integer}:                        {Name: "Int64"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "Int32"},
+		{Type: rewrite.Decimal}:                        {Name: "Decimal"},
+		{Type: rewrite.Time}:                           {Name: "Date"},
+	})
+	naming.Defaults("swift", naming.Convention{
+		Cases: map[naming.Construct]naming.Case{
+			naming.Type:     naming.Pascal,
+			naming.Field:    naming.Camel,
+			naming.Method:   naming.Camel,
+			naming.Constant: naming.Camel,
+			naming.Variable: naming.Camel,
+		},
+		Keywords: naming.Set(
+			"Any", "as", "associatedtype", "break", "case", "catch", "class",
+			"continue", "default", "defer", "deinit", "do", "else", "enum",
+			"extension", "fallthrough", "false", "fileprivate", "for", "func",
+			"guard", "if", "import", "in", "init", "inout", "internal", "is",
+			"let", "nil", "open", "operator", "private", "protocol", "public",
+			"repeat", "rethrows", "return", "self", "Self", "static", "struct",
+			"subscript", "super", "switch", "throw", "throws", "true", "try",
+			"typealias", "var", "where", "while",
+		),
+		Escape: func(name string) string {
+			return "`" + name + "`"
+		},
+	})
+	generators.Register(generators.Single("swift", ".swift", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg)
+	}))
+}
+
+// Render returns the Swift source for the giving package definition.
+//
+// DataDefinition with fields are rendered as Codable structs with a public
+// initializer, while those with only methods are rendered as protocols.
+// Channels, futures and streams can not be coded, so structs can not hold
+// them. Methods returning a FutureDefinition are rendered as async
+// functions, and CommentDefinition are rendered as doc comments of the
+// declaration which follows them. Statements found directly within the
+// package are ignored.
+func Render(pkg rewrite.PackageDefinition) ([]byte, error) {
+	var r = renderer{imports: map[string]bool{"Foundation": true}, types: typemap.For("swift"), names: naming.For("swift")}
+	for _, definition := range pkg.Definitions {
+		r.render(definition)
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	var out bytes.Buffer
+	if pkg.Description != "" {
+		fmt.Fprintf(&out, "%s\n", generators.Comment("// %s", pkg.Description))
+	}
+	if pkg.Version != "" {
+		fmt.Fprintf(&out, "// Version: %s\n", pkg.Version)
+	}
+	var imports = make([]string, 0, len(r.imports))
+	for module := range r.imports {
+		imports = append(imports, module)
+	}
+	sort.Strings(imports)
+	for _, module := range imports {
+		fmt.Fprintf(&out, "import %s\n", module)
+	}
+	out.Write(r.Out.Bytes())
+	return out.Bytes(), nil
+}
+
+type renderer struct {
+	generators.Renderer
+	imports map[string]bool
+	types   typemap.Table
+	names   naming.Convention
+
+	// commented is true if the last declaration was a comment, so the
+	// comment is kept attached to the following declaration.
+	commented bool
+}
+
+func (r *renderer) render(definition rewrite.Applicable) {
+	if definition == nil {
+		return
+	}
+
+	var commented = r.commented
+	r.commented = false
+
+	switch def := definition.Elem().(type) {
+	case rewrite.CommentDefinition:
+		if !commented {
+			r.Printf("\n")
+		}
+		for _, line := range def.Contents {
+			r.Printf("/// %s\n", line)
+		}
+		r.commented = true
+		return
+	case rewrite.DataDefinition:
+		r.doc("", commented, def.Description)
+		if len(def.Fields) == 0 {
+			r.renderProtocol(def)
+			return
+		}
+		r.renderStruct(def)
+	case rewrite.DataTypeDefinition:
+		r.doc("", commented, def.Description)
+		r.Printf("public typealias %s = %s\n", r.names.Name(naming.Type, def.Name), r.typeName(def.Type))
+	case rewrite.TypeDefinition:
+		r.doc("", commented, def.Description)
+		r.Printf("public typealias %s = %s\n", r.names.Name(naming.Type, def.Name), r.typeName(definition))
+	case rewrite.MethodDefinition:
+		r.doc("", commented, def.Description)
+		r.Printf("public %s {\n    fatalError(\"not implemented\")\n}\n", r.signature(def))
+	case rewrite.VariableDefinition:
+		r.doc("", commented, def.Description)
+		r.renderVariable(def)
+	}
+}
+
+func (r *renderer) renderStruct(def rewrite.DataDefinition) {
+	for _, field := range def.Fields {
+		if isRuntime(field.Type) {
+			r.SetErr(fmt.Errorf("swift: %s can not be Codable, its field %q is a channel, future or stream", def.Name, field.Name))
+			return
+		}
+	}
+
+	r.Printf("public struct %s: Codable {\n", r.names.Name(naming.Type, def.Name))
+
+	var renamed bool
+	var params = make([]string, 0, len(def.Fields))
+	for _, field := range def.Fields {
+		var name = r.names.Name(naming.Field, field.Name)
+		var typ = r.typeName(field.Type)
+		renamed = renamed || name != field.Name
+		params = append(params, fmt.Sprintf("%s: %s", name, typ))
+		r.doc("    ", false, field.Description)
+		r.Printf("    public var %s: %s\n", name, typ)
+	}
+
+	// the memberwise initializer of a struct is internal, so public
+	// structs declare their own.
+	r.Printf("\n    public init(%s) {\n", strings.Join(params, ", "))
+	for _, field := range def.Fields {
+		var name = r.names.Name(naming.Field, field.Name)
+		r.Printf("        self.%s = %s\n", name, name)
+	}
+	r.Printf("    }\n")
+
+	// renamed fields are coded with their declared name.
+	if renamed {
+		r.Printf("\n    enum CodingKeys: String, CodingKey {\n")
+		for _, field := range def.Fields {
+			var name = r.names.Name(naming.Field, field.Name)
+			if name == field.Name {
+				r.Printf("        case %s\n", name)
+				continue
+			}
+			r.Printf("        case %s = %q\n", name, field.Name)
+		}
+		r.Printf("    }\n")
+	}
+	for _, method := range def.Methods {
+		r.Printf("\n")
+		r.doc("    ", false, method.Description)
+		r.Printf("    public %s {\n        fatalError(\"not implemented\")\n    }\n", r.signature(method))
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderProtocol(def rewrite.DataDefinition) {
+	r.Printf("public protocol %s {\n", r.names.Name(naming.Type, def.Name))
+	for index, method := range def.Methods {
+		if index != 0 {
+			r.Printf("\n")
+		}
+		r.doc("    ", false, method.Description)
+		r.Printf("    %s\n", r.signature(method))
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderVariable(def rewrite.VariableDefinition) {
+	var keyword = "var"
+	if def.Constant {
+		keyword = "let"
+	}
+
+	var name = r.names.Name(naming.Variable, def.Name)
+	if def.Constant {
+		name = r.names.Name(naming.Constant, def.Name)
+	}
+
+	r.Printf("public %s %s", keyword, name)
+	if def.Type != nil {
+		r.Printf(": %s", r.typeName(def.Type))
+	}
+	if def.Assign != nil && def.Assign.Value != nil {
+		r.Printf(" = %s", generators.Value(def.Assign.Value, "nil"))
+	}
+	r.Printf("\n")
+}
+
+// signature returns the declaration of a function, methods returning a
+// single FutureDefinition are async functions returning its value.
+func (r *renderer) signature(def rewrite.MethodDefinition) string {
+	var args = make([]string, 0, len(def.Arguments))
+	for _, arg := range def.Arguments {
+		args = append(args, fmt.Sprintf("%s: %s", r.names.Name(naming.Variable, arg.Name), r.typeName(arg.Type)))
+	}
+
+	var signature = fmt.Sprintf("func %s(%s)", r.names.Name(naming.Method, def.Name), strings.Join(args, ", "))
+	var returns = def.Returns
+	if len(returns) == 1 && returns[0].Type != nil {
+		if future, ok := returns[0].Type.Elem().(rewrite.FutureDefinition); ok {
+			return fmt.Sprintf("%s async -> %s", signature, r.typeName(future.Type))
+		}
+	}
+
+	switch len(returns) {
+	case 0:
+		return signature
+	case 1:
+		return fmt.Sprintf("%s -> %s", signature, r.typeName(returns[0].Type))
+	}
+
+	var types = make([]string, 0, len(returns))
+	for _, ret := range returns {
+		types = append(types, r.typeName(ret.Type))
+	}
+	return fmt.Sprintf("%s -> (%s)", signature, strings.Join(types, ", "))
+}
+
+// typeName returns the Swift type for the giving type definition.
+func (r *renderer) typeName(definition rewrite.Applicable) string {
+	if definition == nil {
+		return "Any"
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return r.baseType(def)
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return r.typeName(def.Type)
+		}
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.DataDefinition:
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.StreamDefinition:
+		return fmt.Sprintf("AsyncStream<%s>", r.typeName(def.Type))
+	case rewrite.FutureDefinition:
+		return fmt.Sprintf("Task<%s, Error>", r.typeName(def.Type))
+	case rewrite.FieldDefinition:
+		return r.typeName(def.Type)
+	case rewrite.ReturnDefinition:
+		return r.typeName(def.Type)
+	}
+
+	r.SetErr(fmt.Errorf("swift: %T is not a type definition", definition.Elem()))
+	return ""
+}
+
+func (r *renderer) baseType(def rewrite.TypeDefinition) string {
+	var typ, ok = r.types.Lookup(def)
+	if !ok {
+		r.SetErr(fmt.Errorf("swift: base type %q has no Swift equivalent", def.Type))
+		return ""
+	}
+	if typ.Import != "" {
+		r.imports[typ.Import] = true
+	}
+	return typ.Name
+}
+
+// isRuntime returns true if the type is a channel, future or stream, which
+// only exist at runtime and can not be coded.
+func isRuntime(definition rewrite.Applicable) bool {
+	if definition == nil {
+		return false
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.ChannelDefinition, rewrite.FutureDefinition, rewrite.StreamDefinition:
+		return true
+	case rewrite.DataTypeDefinition:
+		return isRuntime(def.Type)
+	}
+	return false
+}
+
+// doc renders a doc comment for a declaration with a description,
+// top level declarations are separated by a blank line unless they
+// follow a comment.
+func (r *renderer) doc(indent string, commented bool, description string) {
+	if indent == "" && !commented {
+		r.Printf("\n")
+	}
+	if description != "" {
+		r.Printf("%s\n", generators.Comment(indent+"/// %s", description))
+	}
+}
@@ -0,0 +1,153 @@
+package swift_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/swift"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User"},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "balance"}, Type: &rewrite.TypeDefinition{Type: rewrite.Decimal}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "joined"}, Type: &rewrite.TypeDefinition{Type: rewrite.Time}},
+		},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.CommentDefinition{Contents: []string{"A registered user."}},
+			user,
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Users", Description: "Looks up users."},
+				Methods: []rewrite.MethodDefinition{
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "get"},
+						Arguments: []rewrite.FieldDefinition{
+							{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+						},
+						Returns: []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: user}}},
+					},
+				},
+			},
+		},
+	}
+
+	var code, err = swift.Render(pkg)
+	require.NoError(t, err)
+	require.Equal(t, `import Foundation
+
+/// A registered user.
+public struct User: Codable {
+    public var name: String
+    public var balance: Decimal
+    public var joined: Date
+
+    public init(name: String, balance: Decimal, joined: Date) {
+        self.name = name
+        self.balance = balance
+        self.joined = joined
+    }
+}
+
+/// Looks up users.
+public protocol Users {
+    func get(id: Int64) async -> User
+}
+`, string(code))
+}
+
+func TestRenderInvalidType(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Point"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "at"}, Type: &rewrite.TypeDefinition{Type: rewrite.Complex}},
+				},
+			},
+		},
+	}
+
+	var _, err = swift.Render(pkg)
+	require.Error(t, err)
+}
+
+func TestRenderRuntimeFields(t *testing.T) {
+	for _, typ := range []rewrite.Applicable{
+		&rewrite.FutureDefinition{Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+		&rewrite.StreamDefinition{Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+		&rewrite.ChannelDefinition{Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+	} {
+		var pkg = rewrite.PackageDefinition{
+			Definitions: []rewrite.Applicable{
+				&rewrite.DataDefinition{
+					BaseDefinition: rewrite.BaseDefinition{Name: "Feed"},
+					Fields:         []rewrite.FieldDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "updates"}, Type: typ}},
+				},
+			},
+		}
+
+		var _, err = swift.Render(pkg)
+		require.EqualError(t, err, `swift: Feed can not be Codable, its field "updates" is a channel, future or stream`)
+	}
+}
+
+func TestRenderRenamed(t *testing.T) {
+	var email = &rewrite.DataTypeDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "email_address"},
+		Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "accounts"},
+		Definitions: []rewrite.Applicable{
+			email,
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "account"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "email_address"}, Type: email},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "active"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+			},
+		},
+	}
+
+	var out, err = swift.Render(pkg)
+	require.NoError(t, err)
+	var code = string(out)
+	require.Contains(t, code, "public typealias EmailAddress = String\n")
+	require.Contains(t, code, "public var emailAddress: EmailAddress\n")
+	require.Contains(t, code, "case emailAddress = \"email_address\"\n")
+}
+
+func TestRenderKeywords(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "search"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "find"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "for"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+				Returns: []rewrite.ReturnDefinition{{Type: &rewrite.TypeDefinition{Type: rewrite.String}}},
+			},
+			&rewrite.VariableDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "if"},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+				Assign:         &rewrite.AssignmentDefinition{Value: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "\"any\""}}},
+			},
+		},
+	}
+
+	var out, err = swift.Render(pkg)
+	require.NoError(t, err)
+	var code = string(out)
+	require.Contains(t, code, "public func find(`for`: String) -> String {")
+	require.Contains(t, code, "public var `if`: String = \"any\"")
+}
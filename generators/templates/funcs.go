@@ -0,0 +1,236 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/naming"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Funcs returns the helpers available to templates, where type and name
+// helpers follow the type mappings and naming convention of target:
+//
+//	type       returns the type name of a type definition
+//	import     returns the import of a base type, empty if it has none
+//	kind       returns what a definition is, e.g "data" or "future"
+//	elem       returns the type wrapped by a field, return, alias, future,
+//	           stream or channel
+//	name       converts a name for a construct, one of "type", "field",
+//	           "method", "constant" or "variable"
+//	camel, pascal, snake, screaming and words convert names to a case
+//	definitions, data, methods and variables return the declarations of a
+//	           package
+//	annotation and hasAnnotation read the annotations of a definition
+//	join, lower, upper, trim, replace, quote and indent format text
+func Funcs(target string) template.FuncMap {
+	var h = helpers{target: target, types: typemap.For(target), names: naming.For(target)}
+	return template.FuncMap{
+		"type":   h.typeName,
+		"import": h.importPath,
+		"kind":   kind,
+		"elem":   elem,
+		"name":   h.name,
+
+		"camel":     naming.Camel.Apply,
+		"pascal":    naming.Pascal.Apply,
+		"snake":     naming.Snake.Apply,
+		"screaming": naming.Screaming.Apply,
+		"words":     naming.Words,
+
+		"definitions": definitions,
+		"data":        data,
+		"methods":     methods,
+		"variables":   variables,
+
+		"annotation":    annotation,
+		"hasAnnotation": hasAnnotation,
+
+		"join":    strings.Join,
+		"lower":   strings.ToLower,
+		"upper":   strings.ToUpper,
+		"trim":    strings.TrimSpace,
+		"replace": strings.ReplaceAll,
+		"quote":   strconv.Quote,
+		"indent":  indent,
+	}
+}
+
+var constructs = map[string]naming.Construct{
+	"type":     naming.Type,
+	"field":    naming.Field,
+	"method":   naming.Method,
+	"constant": naming.Constant,
+	"variable": naming.Variable,
+}
+
+type helpers struct {
+	target string
+	types  typemap.Table
+	names  naming.Convention
+}
+
+func (h helpers) name(construct string, name string) (string, error) {
+	var c, ok = constructs[construct]
+	if !ok {
+		return "", fmt.Errorf("templates: unknown construct %q", construct)
+	}
+	return h.names.Name(c, name), nil
+}
+
+func (h helpers) typeName(definition interface{}) (string, error) {
+	switch def := value(definition).(type) {
+	case rewrite.TypeDefinition:
+		if h.target == "" {
+			return def.Type.String(), nil
+		}
+		if typ, ok := h.types.Lookup(def); ok {
+			return typ.Name, nil
+		}
+		return "", fmt.Errorf("templates: base type %q has no %s type", def.Type, h.target)
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return h.typeName(def.Type)
+		}
+		return h.names.Name(naming.Type, def.Name), nil
+	case rewrite.DataDefinition:
+		return h.names.Name(naming.Type, def.Name), nil
+	case rewrite.FieldDefinition:
+		return h.typeName(def.Type)
+	case rewrite.ReturnDefinition:
+		return h.typeName(def.Type)
+	case nil:
+		return "", fmt.Errorf("templates: missing type definition")
+	}
+	return "", fmt.Errorf("templates: %s has no type name, use kind and elem", kind(definition))
+}
+
+func (h helpers) importPath(definition interface{}) string {
+	if def, ok := value(definition).(rewrite.TypeDefinition); ok {
+		if typ, ok := h.types.Lookup(def); ok {
+			return typ.Import
+		}
+	}
+	return ""
+}
+
+// value returns the definition held by an Applicable, or the definition
+// itself when ranging over the values returned by helpers.
+func value(definition interface{}) interface{} {
+	if applicable, ok := definition.(rewrite.Applicable); ok && applicable != nil {
+		return applicable.Elem()
+	}
+	return definition
+}
+
+func kind(definition interface{}) string {
+	switch value(definition).(type) {
+	case rewrite.TypeDefinition:
+		return "type"
+	case rewrite.DataTypeDefinition:
+		return "alias"
+	case rewrite.DataDefinition:
+		return "data"
+	case rewrite.MethodDefinition:
+		return "method"
+	case rewrite.FieldDefinition:
+		return "field"
+	case rewrite.ReturnDefinition:
+		return "return"
+	case rewrite.VariableDefinition:
+		return "variable"
+	case rewrite.FutureDefinition:
+		return "future"
+	case rewrite.StreamDefinition:
+		return "stream"
+	case rewrite.ChannelDefinition:
+		return "channel"
+	case rewrite.CommentDefinition:
+		return "comment"
+	case rewrite.AnnotationDefinition:
+		return "annotation"
+	}
+	return ""
+}
+
+func elem(definition interface{}) (rewrite.Applicable, error) {
+	switch def := value(definition).(type) {
+	case rewrite.FieldDefinition:
+		return def.Type, nil
+	case rewrite.ReturnDefinition:
+		return def.Type, nil
+	case rewrite.DataTypeDefinition:
+		return def.Type, nil
+	case rewrite.FutureDefinition:
+		return def.Type, nil
+	case rewrite.StreamDefinition:
+		return def.Type, nil
+	case rewrite.ChannelDefinition:
+		return def.Type, nil
+	}
+	return nil, fmt.Errorf("templates: %T wraps no type", value(definition))
+}
+
+func definitions(pkg rewrite.PackageDefinition) []interface{} {
+	var defs = make([]interface{}, 0, len(pkg.Definitions))
+	for _, definition := range pkg.Definitions {
+		if definition != nil {
+			defs = append(defs, definition.Elem())
+		}
+	}
+	return defs
+}
+
+func data(pkg rewrite.PackageDefinition) []rewrite.DataDefinition {
+	var defs []rewrite.DataDefinition
+	for _, definition := range definitions(pkg) {
+		if def, ok := definition.(rewrite.DataDefinition); ok {
+			defs = append(defs, def)
+		}
+	}
+	return defs
+}
+
+func methods(pkg rewrite.PackageDefinition) []rewrite.MethodDefinition {
+	var defs []rewrite.MethodDefinition
+	for _, definition := range definitions(pkg) {
+		if def, ok := definition.(rewrite.MethodDefinition); ok {
+			defs = append(defs, def)
+		}
+	}
+	return defs
+}
+
+func variables(pkg rewrite.PackageDefinition) []rewrite.VariableDefinition {
+	var defs []rewrite.VariableDefinition
+	for _, definition := range definitions(pkg) {
+		if def, ok := definition.(rewrite.VariableDefinition); ok {
+			defs = append(defs, def)
+		}
+	}
+	return defs
+}
+
+func annotation(annotations rewrite.Annotations, name string) string {
+	var def, _ = annotations.Get(name)
+	return def.Content
+}
+
+func hasAnnotation(annotations rewrite.Annotations, name string) bool {
+	return annotations.Has(name)
+}
+
+// indent prefixes every non empty line of text with spaces.
+func indent(spaces int, text string) string {
+	var prefix = strings.Repeat(" ", spaces)
+	var lines = strings.Split(text, "\n")
+	for index, line := range lines {
+		if line != "" {
+			lines[index] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
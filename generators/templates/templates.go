@@ -0,0 +1,149 @@
+// Package templates renders a rewrite.PackageDefinition through user
+// supplied text/template files, for outputs which do not warrant a backend
+// of their own, e.g config files, client wrappers or docs.
+//
+// Every template renders one file with the package definition as its data,
+// and may use the helpers of Funcs to map types, convert names and walk the
+// definitions of the package, e.g:
+//
+//	{{range data .}}
+//	export const {{name "constant" .Name}}_FIELDS = [{{range .Fields}}"{{.Name}}", {{end}}];
+//	{{end}}
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+)
+
+// Extension is stripped from the name of template files to name the files
+// they render.
+const Extension = ".tmpl"
+
+// Options configures how templates are rendered.
+type Options struct {
+	// Target is the generator whose type mappings and naming convention
+	// are used by the helpers, e.g "go". Base types are rendered by their
+	// rewrite name and names are preserved if empty.
+	Target string
+}
+
+// Parse returns a Generator registered as name, which renders a file for
+// each of the templates in sources. Sources are keyed by the name of the
+// file they render, which is itself a template executed with the package,
+// e.g "{{.Name}}_client.go".
+func Parse(name string, options Options, sources map[string]string) (generators.Generator, error) {
+	var files = make([]file, 0, len(sources))
+	for fileName, source := range sources {
+		var nameTemplate, err = template.New(fileName).Funcs(Funcs(options.Target)).Parse(fileName)
+		if err != nil {
+			return nil, fmt.Errorf("templates: file name %q: %w", fileName, err)
+		}
+
+		content, err := template.New(fileName).Funcs(Funcs(options.Target)).Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("templates: %w", err)
+		}
+
+		files = append(files, file{name: nameTemplate, content: content})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].name.Name() < files[j].name.Name()
+	})
+
+	var renderer = renderer{target: options.Target, files: files}
+	return generators.Multiple(name, renderer.extensions(), renderer.render), nil
+}
+
+// ParseFiles returns a Generator like Parse, reading the templates from the
+// giving paths. Each template renders a file named after it without its
+// Extension, e.g "{{.Name}}.yaml.tmpl" renders "billing.yaml" for a package
+// named billing.
+func ParseFiles(name string, options Options, paths ...string) (generators.Generator, error) {
+	var sources = make(map[string]string, len(paths))
+	for _, filePath := range paths {
+		var content, err = ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("templates: %w", err)
+		}
+
+		var fileName = strings.TrimSuffix(filepath.Base(filePath), Extension)
+		if _, ok := sources[fileName]; ok {
+			return nil, fmt.Errorf("templates: file %q is rendered by more than one template", fileName)
+		}
+		sources[fileName] = string(content)
+	}
+	return Parse(name, options, sources)
+}
+
+type file struct {
+	name    *template.Template
+	content *template.Template
+}
+
+type renderer struct {
+	target string
+	files  []file
+}
+
+// extensions returns the extensions of the rendered files.
+func (r renderer) extensions() []string {
+	var seen = map[string]bool{}
+	var extensions []string
+	for _, file := range r.files {
+		var extension = path.Ext(file.name.Name())
+		if extension == "" || seen[extension] {
+			continue
+		}
+		seen[extension] = true
+		extensions = append(extensions, extension)
+	}
+	sort.Strings(extensions)
+	return extensions
+}
+
+func (r renderer) render(pkg rewrite.PackageDefinition) (map[string][]byte, error) {
+	// helpers are bound again on every render, so overrides of type
+	// mappings and naming conventions made after parsing are used.
+	var funcs = Funcs(r.target)
+
+	var contents = make(map[string][]byte, len(r.files))
+	for _, file := range r.files {
+		var name, err = execute(file.name, funcs, pkg)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := contents[string(name)]; ok {
+			return nil, fmt.Errorf("templates: file %q is rendered by more than one template", name)
+		}
+
+		content, err := execute(file.content, funcs, pkg)
+		if err != nil {
+			return nil, err
+		}
+		contents[string(name)] = content
+	}
+	return contents, nil
+}
+
+func execute(tmpl *template.Template, funcs template.FuncMap, pkg rewrite.PackageDefinition) ([]byte, error) {
+	var clone, err = tmpl.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := clone.Funcs(funcs).Execute(&out, pkg); err != nil {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,78 @@
+package templates_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/templates"
+	"github.com/stretchr/testify/require"
+)
+
+var billing = rewrite.PackageDefinition{
+	BaseDefinition: rewrite.BaseDefinition{Name: "billing"},
+	Definitions: []rewrite.Applicable{
+		&rewrite.DataDefinition{
+			BaseDefinition: rewrite.BaseDefinition{Name: "invoice"},
+			Fields: []rewrite.FieldDefinition{
+				{BaseDefinition: rewrite.BaseDefinition{Name: "invoice_id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+				{BaseDefinition: rewrite.BaseDefinition{Name: "issued"}, Type: &rewrite.TypeDefinition{Type: rewrite.Time}},
+			},
+		},
+		&rewrite.MethodDefinition{
+			BaseDefinition: rewrite.BaseDefinition{Name: "Pay"},
+			Returns: []rewrite.ReturnDefinition{
+				{Type: &rewrite.FutureDefinition{Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "invoice"}}}},
+			},
+		},
+	},
+}
+
+func TestParse(t *testing.T) {
+	var generator, err = templates.Parse("wrapper", templates.Options{Target: "go"}, map[string]string{
+		"{{.Name}}_fields.txt": `{{range data .}}{{pascal .Name}}:{{range .Fields}} {{name "field" .Name}} {{type .}} {{import .Type}};{{end}}{{end}}
+{{range methods .}}{{.Name}} {{range .Returns}}{{kind .Type}} {{type (elem .Type)}}{{end}}{{end}}
+`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "wrapper", generator.Name())
+	require.Equal(t, []string{".txt"}, generator.Extensions())
+
+	files, err := generator.Generate(billing)
+	require.NoError(t, err)
+	require.Equal(t, []generators.File{{
+		Name:    "billing_fields.txt",
+		Content: []byte("Invoice: InvoiceID int64 ; Issued Time time;\nPay future invoice\n"),
+	}}, files)
+}
+
+func TestParseErrors(t *testing.T) {
+	var _, err = templates.Parse("broken", templates.Options{}, map[string]string{"out.txt": "{{range}}"})
+	require.Error(t, err)
+
+	generator, err := templates.Parse("unknown", templates.Options{}, map[string]string{"out.txt": `{{name "package" .Name}}`})
+	require.NoError(t, err)
+	_, err = generator.Generate(billing)
+	require.Error(t, err)
+}
+
+func TestParseFiles(t *testing.T) {
+	var dir, err = ioutil.TempDir("", "templates")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var path = filepath.Join(dir, "{{.Name}}.yaml.tmpl")
+	require.NoError(t, ioutil.WriteFile(path, []byte("package: {{.Name}}\n{{range definitions .}}- {{kind .}}\n{{end}}"), 0644))
+
+	generator, err := templates.ParseFiles("config", templates.Options{}, path)
+	require.NoError(t, err)
+
+	files, err := generator.Generate(billing)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "billing.yaml", files[0].Name)
+	require.Equal(t, "package: billing\n- data\n- method\n", string(files[0].Content))
+}
@@ -0,0 +1,266 @@
+// Package thrift renders a rewrite.PackageDefinition into Apache Thrift IDL
+// with structs, exceptions and services.
+package thrift
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Annotations understood by the thrift backend.
+const (
+	// ExceptionAnnotation marks a DataDefinition as an exception.
+	ExceptionAnnotation = "exception"
+
+	// FieldIDAnnotation pins the field id of a FieldDefinition, its content
+	// being the id, e.g "3".
+	FieldIDAnnotation = "field_id"
+
+	// ThrowsAnnotation lists the exceptions a MethodDefinition throws, its
+	// content being the comma separated exception names.
+	ThrowsAnnotation = "throws"
+)
+
+// fieldIDs numbers the fields of structs and exceptions and the arguments
+// of service methods, ids being i16.
+var fieldIDs = generators.FieldNumbers{Annotation: FieldIDAnnotation, Max: 1<<15 - 1}
+
+func init() {
+	typemap.Defaults("thrift", typemap.Table{
+		{Type: rewrite.String}:                         {Name: "string"},
+		{Type: rewrite.Rune}:                           {Name: "i32"},
+		{Type: rewrite.Integer}:                        {Name: "i64"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "i32"},
+		{Type: rewrite.Decimal}:                        {Name: "double"},
+		{Type: rewrite.Time}:                           {Name: "i64"},
+	})
+	generators.Register(generators.Single("thrift", ".thrift", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg)
+	}))
+}
+
+// Render returns the Thrift IDL for the giving package definition.
+//
+// DataDefinition with fields are rendered as structs, or exceptions when
+// annotated with ExceptionAnnotation, where every field must pin its id with
+// FieldIDAnnotation. DataDefinition with only methods are rendered as
+// services, whose method arguments pin their ids the same way, so structs
+// and exceptions can not declare methods. DataDefinition without fields or
+// methods are rendered as empty structs. Named types are rendered as
+// typedefs and constant variables as consts. Time is rendered as i64 milliseconds
+// since the unix epoch, as Thrift has no time type.
+func Render(pkg rewrite.PackageDefinition) ([]byte, error) {
+	var r = renderer{Renderer: generators.Renderer{DocFormat: "// %s"}, includes: map[string]bool{}, types: typemap.For("thrift")}
+	for _, definition := range pkg.Definitions {
+		r.render(definition)
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	var out bytes.Buffer
+	if pkg.Description != "" {
+		fmt.Fprintf(&out, "%s\n", generators.Comment("// %s", pkg.Description))
+	}
+	if pkg.Version != "" {
+		fmt.Fprintf(&out, "// Version: %s\n", pkg.Version)
+	}
+	var includes = make([]string, 0, len(r.includes))
+	for include := range r.includes {
+		includes = append(includes, include)
+	}
+	sort.Strings(includes)
+	if len(includes) != 0 && out.Len() != 0 {
+		out.WriteString("\n")
+	}
+	for _, include := range includes {
+		fmt.Fprintf(&out, "include %q\n", include)
+	}
+	if pkg.Name != "" {
+		if out.Len() != 0 {
+			out.WriteString("\n")
+		}
+		fmt.Fprintf(&out, "namespace * %s\n", pkg.Name)
+	}
+	out.Write(r.Out.Bytes())
+	return out.Bytes(), nil
+}
+
+type renderer struct {
+	generators.Renderer
+	includes map[string]bool
+	types    typemap.Table
+}
+
+func (r *renderer) render(definition rewrite.Applicable) {
+	if definition == nil {
+		return
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.CommentDefinition:
+		r.Printf("\n")
+		for _, line := range def.Contents {
+			r.Printf("// %s\n", line)
+		}
+	case rewrite.DataDefinition:
+		switch {
+		case def.Annotations.Has(ExceptionAnnotation):
+			r.renderStruct("exception", def)
+		case len(def.Fields) == 0 && len(def.Methods) != 0:
+			r.renderService(def)
+		default:
+			r.renderStruct("struct", def)
+		}
+	case rewrite.DataTypeDefinition:
+		r.Doc("", def.Description)
+		r.Printf("typedef %s %s\n", r.fieldType(def.Type), def.Name)
+	case rewrite.TypeDefinition:
+		r.Doc("", def.Description)
+		r.Printf("typedef %s %s\n", r.fieldType(definition), def.Name)
+	case rewrite.VariableDefinition:
+		if !def.Constant || def.Type == nil || def.Assign == nil || def.Assign.Value == nil {
+			r.SetErr(fmt.Errorf("thrift: variable %q must be a constant with a type and value", def.Name))
+			return
+		}
+		r.Doc("", def.Description)
+		r.Printf("const %s %s = %s\n", r.fieldType(def.Type), def.Name, generators.Value(def.Assign.Value, ""))
+	case rewrite.MethodDefinition:
+		r.SetErr(fmt.Errorf("thrift: method %q must be declared on a service", def.Name))
+	}
+}
+
+func (r *renderer) renderStruct(kind string, def rewrite.DataDefinition) {
+	if len(def.Methods) != 0 {
+		r.SetErr(fmt.Errorf("thrift: %s %q declares methods, which must be declared on a service without fields", kind, def.Name))
+		return
+	}
+
+	var ids, err = fieldIDs.Numbers(def.Fields)
+	if err != nil {
+		r.SetErr(fmt.Errorf("thrift: %s %q: %w", kind, def.Name, err))
+		return
+	}
+
+	r.Doc("", def.Description)
+	r.Printf("%s %s {\n", kind, def.Name)
+	for index, field := range def.Fields {
+		r.Doc("\t", field.Description)
+		r.Printf("\t%d: %s %s,\n", ids[index], r.fieldType(field.Type), field.Name)
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderService(def rewrite.DataDefinition) {
+	r.Doc("", def.Description)
+	r.Printf("service %s {\n", def.Name)
+	for _, method := range def.Methods {
+		var ids, err = fieldIDs.Numbers(method.Arguments)
+		if err != nil {
+			r.SetErr(fmt.Errorf("thrift: method %q of service %q: %w", method.Name, def.Name, err))
+			return
+		}
+
+		r.Doc("\t", method.Description)
+		var args = make([]string, 0, len(method.Arguments))
+		for index, arg := range method.Arguments {
+			args = append(args, fmt.Sprintf("%d: %s %s", ids[index], r.fieldType(arg.Type), arg.Name))
+		}
+		r.Printf("\t%s %s(%s)%s,\n", r.returnType(method), method.Name, strings.Join(args, ", "), throws(method))
+	}
+	r.Printf("}\n")
+}
+
+// returnType returns the Thrift type returned by a method, futures are
+// rendered as their value as Thrift calls are asynchronous by transport.
+func (r *renderer) returnType(method rewrite.MethodDefinition) string {
+	switch len(method.Returns) {
+	case 0:
+		return "void"
+	case 1:
+	default:
+		r.SetErr(fmt.Errorf("thrift: method %q returns more than one value", method.Name))
+		return ""
+	}
+
+	var definition = method.Returns[0].Type
+	if definition != nil {
+		if future, ok := definition.Elem().(rewrite.FutureDefinition); ok {
+			if future.Type == nil {
+				return "void"
+			}
+			definition = future.Type
+		}
+	}
+	return r.fieldType(definition)
+}
+
+// throws returns the throws clause of a method annotated with
+// ThrowsAnnotation.
+func throws(method rewrite.MethodDefinition) string {
+	var annotation, ok = method.Annotations.Get(ThrowsAnnotation)
+	if !ok {
+		return ""
+	}
+
+	var exceptions []string
+	for _, name := range strings.Split(annotation.Content, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			var field = strings.ToLower(name[:1]) + name[1:]
+			exceptions = append(exceptions, fmt.Sprintf("%d: %s %s", len(exceptions)+1, name, field))
+		}
+	}
+	if len(exceptions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" throws (%s)", strings.Join(exceptions, ", "))
+}
+
+// fieldType returns the Thrift type of the giving type definition, streams
+// are rendered as lists.
+func (r *renderer) fieldType(definition rewrite.Applicable) string {
+	if definition == nil {
+		r.SetErr(fmt.Errorf("thrift: missing type definition"))
+		return ""
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return r.baseType(def)
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return r.fieldType(def.Type)
+		}
+		return def.Name
+	case rewrite.DataDefinition:
+		return def.Name
+	case rewrite.StreamDefinition:
+		return fmt.Sprintf("list<%s>", r.fieldType(def.Type))
+	case rewrite.FieldDefinition:
+		return r.fieldType(def.Type)
+	case rewrite.ReturnDefinition:
+		return r.fieldType(def.Type)
+	}
+
+	r.SetErr(fmt.Errorf("thrift: %T can not be expressed as a Thrift type", definition.Elem()))
+	return ""
+}
+
+func (r *renderer) baseType(def rewrite.TypeDefinition) string {
+	var typ, ok = r.types.Lookup(def)
+	if !ok {
+		r.SetErr(fmt.Errorf("thrift: base type %q has no Thrift equivalent", def.Type))
+		return ""
+	}
+	if typ.Import != "" {
+		r.includes[typ.Import] = true
+	}
+	return typ.Name
+}
@@ -0,0 +1,119 @@
+package thrift_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/thrift"
+	"github.com/stretchr/testify/require"
+)
+
+func id(content string) rewrite.Annotations {
+	return rewrite.Annotations{{BaseDefinition: rewrite.BaseDefinition{Name: thrift.FieldIDAnnotation}, Content: content}}
+}
+
+func TestRender(t *testing.T) {
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "A registered user."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}, Annotations: id("2")},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}, Annotations: id("1")},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "joined"}, Type: &rewrite.TypeDefinition{Type: rewrite.Time}, Annotations: id("3")},
+		},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models"},
+		Definitions: []rewrite.Applicable{
+			user,
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "NotFound"},
+				Annotations:    rewrite.Annotations{{BaseDefinition: rewrite.BaseDefinition{Name: thrift.ExceptionAnnotation}}},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "message"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}, Annotations: id("1")},
+				},
+			},
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Users"},
+				Methods: []rewrite.MethodDefinition{
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "get"},
+						Annotations:    rewrite.Annotations{{BaseDefinition: rewrite.BaseDefinition{Name: thrift.ThrowsAnnotation}, Content: "NotFound"}},
+						Arguments: []rewrite.FieldDefinition{
+							{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}, Annotations: id("1")},
+						},
+						Returns: []rewrite.ReturnDefinition{{Type: &rewrite.FutureDefinition{Type: user}}},
+					},
+					{
+						BaseDefinition: rewrite.BaseDefinition{Name: "list"},
+						Returns:        []rewrite.ReturnDefinition{{Type: &rewrite.StreamDefinition{Type: user}}},
+					},
+				},
+			},
+			&rewrite.VariableDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "LIMIT"},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32},
+				Assign:         &rewrite.AssignmentDefinition{Value: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "10"}}},
+				Constant:       true,
+			},
+		},
+	}
+
+	var code, err = thrift.Render(pkg)
+	require.NoError(t, err)
+	require.Equal(t, `namespace * models
+
+// A registered user.
+struct User {
+	2: i64 id,
+	1: string name,
+	3: i64 joined,
+}
+
+exception NotFound {
+	1: string message,
+}
+
+service Users {
+	User get(1: i64 id) throws (1: NotFound notFound),
+	list<User> list(),
+}
+
+const i32 LIMIT = 10
+`, string(code))
+}
+
+func TestRenderInvalidStructs(t *testing.T) {
+	var text = &rewrite.TypeDefinition{Type: rewrite.String}
+	for _, def := range []*rewrite.DataDefinition{
+		{Fields: []rewrite.FieldDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: text}}},
+		{Fields: []rewrite.FieldDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: text, Annotations: id("32768")}}},
+		{
+			Fields:  []rewrite.FieldDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: text, Annotations: id("1")}},
+			Methods: []rewrite.MethodDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "reload"}}},
+		},
+		{
+			Methods: []rewrite.MethodDefinition{{
+				BaseDefinition: rewrite.BaseDefinition{Name: "get"},
+				Arguments:      []rewrite.FieldDefinition{{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: text}},
+			}},
+		},
+	} {
+		def.Name = "User"
+		var _, err = thrift.Render(rewrite.PackageDefinition{Definitions: []rewrite.Applicable{def}})
+		require.Error(t, err)
+	}
+}
+
+func TestRenderEmptyStruct(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "health"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "Ping"}},
+		},
+	}
+
+	var code, err = thrift.Render(pkg)
+	require.NoError(t, err)
+	require.Equal(t, "namespace * health\n\nstruct Ping {\n}\n", string(code))
+}
@@ -0,0 +1,138 @@
+// Package typemap maps rewrite base types to the native types of each
+// generator, with defaults declared by generators and user overrides.
+//
+// Generators declare their defaults with Defaults when their package is
+// imported, and look up their table with For when rendering. Overrides
+// replace a default for every later render, e.g:
+//
+//	typemap.Override("go", typemap.Key{Type: rewrite.Decimal}, typemap.Type{
+//		Name:   "Decimal",
+//		Import: "github.com/shopspring/decimal",
+//	})
+package typemap
+
+import (
+	"sync"
+
+	"github.com/influx6/rewrite"
+)
+
+// Type defines the native type a base type maps to.
+type Type struct {
+	// Name is the type as written in the target, e.g "Instant".
+	Name string
+
+	// Import is what must be imported for Name to resolve, in the notation
+	// of the generator, e.g "java.time.Instant" for java or "time" for go.
+	// It is empty if nothing needs to be imported.
+	Import string
+
+	// Format refines the type for schema based generators, e.g the format
+	// of a JSON Schema or the logical type of an Avro schema.
+	Format string
+}
+
+// Key identifies a base type with a memory layout, where the zero Memory
+// (rewrite.Bit64) is the mapping used for layouts without a mapping of
+// their own.
+type Key struct {
+	Type   rewrite.BaseType
+	Memory rewrite.MemoryLayout
+}
+
+// Table maps base types to native types.
+type Table map[Key]Type
+
+// Lookup returns the type mapped to the giving type definition, falling
+// back to the mapping of its base type with the default memory layout.
+func (t Table) Lookup(def rewrite.TypeDefinition) (Type, bool) {
+	if typ, ok := t[Key{Type: def.Type, Memory: def.Memory}]; ok {
+		return typ, true
+	}
+	var typ, ok = t[Key{Type: def.Type}]
+	return typ, ok
+}
+
+// Mappings holds the tables of generators by generator name, it is safe
+// for concurrent use.
+type Mappings struct {
+	mu        sync.RWMutex
+	defaults  map[string]Table
+	overrides map[string]Table
+}
+
+// New returns a new empty Mappings.
+func New() *Mappings {
+	return &Mappings{defaults: map[string]Table{}, overrides: map[string]Table{}}
+}
+
+// Defaults sets the default table of a generator.
+func (m *Mappings) Defaults(generator string, table Table) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.defaults[generator] = copyTable(table)
+}
+
+// Override maps the key to the giving type for a generator, taking
+// precedence over its default for that key only, other memory layouts of
+// the base type keep their own mappings.
+func (m *Mappings) Override(generator string, key Key, typ Type) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.overrides[generator] == nil {
+		m.overrides[generator] = Table{}
+	}
+	m.overrides[generator][key] = typ
+}
+
+// Reset removes the overrides of a generator.
+func (m *Mappings) Reset(generator string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.overrides, generator)
+}
+
+// Table returns the table of a generator, its defaults with overrides
+// applied.
+func (m *Mappings) Table(generator string) Table {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var table = copyTable(m.defaults[generator])
+	for key, typ := range m.overrides[generator] {
+		table[key] = typ
+	}
+	return table
+}
+
+func copyTable(table Table) Table {
+	var copied = make(Table, len(table))
+	for key, typ := range table {
+		copied[key] = typ
+	}
+	return copied
+}
+
+// Default holds the mappings used by the generators of this module.
+var Default = New()
+
+// Defaults sets the default table of a generator in Default.
+func Defaults(generator string, table Table) {
+	Default.Defaults(generator, table)
+}
+
+// Override maps the key to the giving type for a generator in Default.
+func Override(generator string, key Key, typ Type) {
+	Default.Override(generator, key, typ)
+}
+
+// Reset removes the overrides of a generator in Default.
+func Reset(generator string) {
+	Default.Reset(generator)
+}
+
+// For returns the table of a generator in Default.
+func For(generator string) Table {
+	return Default.Table(generator)
+}
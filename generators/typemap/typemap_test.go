@@ -0,0 +1,44 @@
+package typemap_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/typemap"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMappings(t *testing.T) {
+	var mappings = typemap.New()
+	mappings.Defaults("kotlin", typemap.Table{
+		{Type: rewrite.Integer}:                        {Name: "Long"},
+		{Type: rewrite.Integer, Memory: rewrite.Bit32}: {Name: "Int"},
+		{Type: rewrite.Decimal}:                        {Name: "Double"},
+		{Type: rewrite.Decimal, Memory: rewrite.Bit32}: {Name: "Float"},
+	})
+
+	var table = mappings.Table("kotlin")
+	var typ, ok = table.Lookup(rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32})
+	require.True(t, ok)
+	require.Equal(t, "Int", typ.Name)
+
+	_, ok = table.Lookup(rewrite.TypeDefinition{Type: rewrite.Time})
+	require.False(t, ok)
+
+	mappings.Override("kotlin", typemap.Key{Type: rewrite.Decimal}, typemap.Type{Name: "BigDecimal", Import: "java.math.BigDecimal"})
+	mappings.Override("kotlin", typemap.Key{Type: rewrite.Integer, Memory: rewrite.Bit32}, typemap.Type{Name: "Short"})
+
+	table = mappings.Table("kotlin")
+	typ, _ = table.Lookup(rewrite.TypeDefinition{Type: rewrite.Decimal})
+	require.Equal(t, typemap.Type{Name: "BigDecimal", Import: "java.math.BigDecimal"}, typ)
+	typ, _ = table.Lookup(rewrite.TypeDefinition{Type: rewrite.Decimal, Memory: rewrite.Bit32})
+	require.Equal(t, "Float", typ.Name)
+	typ, _ = table.Lookup(rewrite.TypeDefinition{Type: rewrite.Integer})
+	require.Equal(t, "Long", typ.Name)
+	typ, _ = table.Lookup(rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32})
+	require.Equal(t, "Short", typ.Name)
+
+	mappings.Reset("kotlin")
+	typ, _ = mappings.Table("kotlin").Lookup(rewrite.TypeDefinition{Type: rewrite.Decimal})
+	require.Equal(t, "Double", typ.Name)
+}
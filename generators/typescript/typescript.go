@@ -0,0 +1,365 @@
+// Package typescript renders a rewrite.PackageDefinition into TypeScript
+// source, for consumers which share models with the Go definitions.
+package typescript
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators"
+	"github.com/influx6/rewrite/generators/naming"
+	"github.com/influx6/rewrite/generators/typemap"
+)
+
+// Options configures how definitions are rendered.
+type Options struct {
+	// Classes renders DataDefinition as classes instead of interfaces.
+	Classes bool
+}
+
+func init() {
+	typemap.Defaults("typescript", typemap.Table{
+		{Type: rewrite.String}:  {Name: "string"},
+		{Type: rewrite.Rune}:    {Name: "string"},
+		{Type: rewrite.Integer}: {Name: "number"},
+		{Type: rewrite.Decimal}: {Name: "number"},
+		{Type: rewrite.Complex}: {Name: "[number, number]"},
+		{Type: rewrite.Time}:    {Name: "Date"},
+	})
+	naming.Defaults("typescript", naming.Convention{
+		Cases: map[naming.Construct]naming.Case{
+			naming.Type:     naming.Pascal,
+			naming.Field:    naming.Preserve,
+			naming.Method:   naming.Camel,
+			naming.Variable: naming.Camel,
+		},
+		Keywords: naming.Set(
+			"break", "case", "catch", "class", "const", "continue", "debugger",
+			"default", "delete", "do", "else", "enum", "export", "extends",
+			"false", "finally", "for", "function", "if", "implements", "import",
+			"in", "instanceof", "interface", "let", "new", "null", "package",
+			"private", "protected", "public", "return", "static", "super",
+			"switch", "this", "throw", "true", "try", "typeof", "var", "void",
+			"while", "with", "yield",
+		),
+	})
+	generators.Register(generators.Single("typescript", ".ts", func(pkg rewrite.PackageDefinition) ([]byte, error) {
+		return Render(pkg, Options{})
+	}))
+}
+
+// Render returns the TypeScript source for the giving package definition.
+//
+// Only declarations (data, types, methods, variables and constants) are
+// rendered, statements found directly within the package are ignored.
+// Fields keep their declared names, so interfaces match the JSON payloads
+// they describe: names are never escaped, keywords being valid property
+// names, and names which are not identifiers are quoted. Override naming.Field of the typescript convention, e.g
+// with naming.Camel, for payloads whose keys are converted elsewhere.
+func Render(pkg rewrite.PackageDefinition, options Options) ([]byte, error) {
+	var r = renderer{
+		Renderer: generators.Renderer{
+			DocFormat: "/** %s */",
+			DocBlock:  [3]string{"/**", " * ", " */"},
+			DocEscape: generators.EscapeCommentEnd,
+		},
+		options: options,
+		imports: map[string]map[string]bool{},
+		types:   typemap.For("typescript"),
+		names:   naming.For("typescript"),
+	}
+	for _, definition := range pkg.Definitions {
+		r.render(definition)
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	var out bytes.Buffer
+	if pkg.Description != "" {
+		fmt.Fprintf(&out, "%s\n", generators.Comment("// %s", pkg.Description))
+	}
+	if pkg.Version != "" {
+		fmt.Fprintf(&out, "// Version: %s\n", pkg.Version)
+	}
+
+	var modules = make([]string, 0, len(r.imports))
+	for module := range r.imports {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	for _, module := range modules {
+		var names = make([]string, 0, len(r.imports[module]))
+		for name := range r.imports[module] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(&out, "import { %s } from %q;\n", strings.Join(names, ", "), module)
+	}
+
+	out.Write(r.Out.Bytes())
+	return out.Bytes(), nil
+}
+
+type renderer struct {
+	generators.Renderer
+	imports map[string]map[string]bool
+	types   typemap.Table
+	names   naming.Convention
+	options Options
+}
+
+func (r *renderer) render(definition rewrite.Applicable) {
+	if definition == nil {
+		return
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.CommentDefinition:
+		r.Printf("\n")
+		for _, line := range def.Contents {
+			r.Printf("// %s\n", line)
+		}
+	case rewrite.AnnotationDefinition:
+		r.Printf("\n// %s\n", generators.Annotation(def))
+	case rewrite.DataDefinition:
+		if r.options.Classes {
+			r.renderClass(def)
+			return
+		}
+		r.renderInterface(def)
+	case rewrite.DataTypeDefinition:
+		r.Doc("", def.Description)
+		r.Printf("export type %s = %s;\n", r.names.Name(naming.Type, def.Name), r.typeName(def.Type))
+	case rewrite.TypeDefinition:
+		r.Doc("", def.Description)
+		r.Printf("export type %s = %s;\n", r.names.Name(naming.Type, def.Name), r.typeName(definition))
+	case rewrite.MethodDefinition:
+		r.Doc("", def.Description)
+		r.Printf("export function %s {\n", r.signature(def))
+		r.stub("\t", def)
+		r.Printf("}\n")
+	case rewrite.VariableDefinition:
+		r.renderVariable(def)
+	}
+}
+
+func (r *renderer) renderInterface(def rewrite.DataDefinition) {
+	r.Doc("", def.Description)
+	r.Printf("export interface %s {\n", r.names.Name(naming.Type, def.Name))
+	for _, field := range def.Fields {
+		r.Doc("\t", field.Description)
+		r.Printf("\t%s: %s;\n", r.property(field.Name), r.typeName(field.Type))
+	}
+	for _, method := range def.Methods {
+		r.Doc("\t", method.Description)
+		r.Printf("\t%s;\n", r.signature(method))
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderClass(def rewrite.DataDefinition) {
+	r.Doc("", def.Description)
+	r.Printf("export class %s {\n", r.names.Name(naming.Type, def.Name))
+
+	// fields whose property can not be a constructor parameter, being a
+	// keyword or not an identifier, are declared and assigned instead.
+	var assigned []rewrite.FieldDefinition
+	for _, field := range def.Fields {
+		if !r.parameter(field.Name) {
+			r.Doc("\t", field.Description)
+			r.Printf("\t%s: %s;\n", r.property(field.Name), r.typeName(field.Type))
+			assigned = append(assigned, field)
+		}
+	}
+	if len(assigned) != 0 {
+		r.Printf("\n")
+	}
+
+	if len(def.Fields) != 0 {
+		r.Printf("\tconstructor(\n")
+		for _, field := range def.Fields {
+			if r.parameter(field.Name) {
+				r.Doc("\t\t", field.Description)
+				r.Printf("\t\tpublic %s: %s,\n", r.property(field.Name), r.typeName(field.Type))
+				continue
+			}
+			r.Printf("\t\t%s: %s,\n", r.names.Name(naming.Variable, field.Name), r.typeName(field.Type))
+		}
+		if len(assigned) == 0 {
+			r.Printf("\t) {}\n")
+		} else {
+			r.Printf("\t) {\n")
+			for _, field := range assigned {
+				r.Printf("\t\tthis%s = %s;\n", r.member(field.Name), r.names.Name(naming.Variable, field.Name))
+			}
+			r.Printf("\t}\n")
+		}
+	}
+
+	for index, method := range def.Methods {
+		if index != 0 || len(def.Fields) != 0 {
+			r.Printf("\n")
+		}
+		r.Doc("\t", method.Description)
+		r.Printf("\t%s {\n", r.signature(method))
+		r.stub("\t\t", method)
+		r.Printf("\t}\n")
+	}
+	r.Printf("}\n")
+}
+
+func (r *renderer) renderVariable(def rewrite.VariableDefinition) {
+	var keyword = "let"
+	if def.Constant {
+		keyword = "const"
+	}
+
+	var name = r.names.Name(naming.Variable, def.Name)
+	if def.Constant {
+		name = r.names.Name(naming.Constant, def.Name)
+	}
+
+	r.Doc("", def.Description)
+	r.Printf("export %s %s", keyword, name)
+	if def.Type != nil {
+		r.Printf(": %s", r.typeName(def.Type))
+	}
+	if def.Assign != nil && def.Assign.Value != nil {
+		r.Printf(" = %s", generators.Value(def.Assign.Value, "undefined"))
+	}
+	r.Printf(";\n")
+}
+
+// property returns the property name of a field, which is converted to
+// the case of fields but never escaped, as keywords are valid property
+// names and escaping would change the key of the field in JSON. Names
+// which are not identifiers, e.g "user-id", are quoted.
+func (r *renderer) property(name string) string {
+	var property = r.names.Cases[naming.Field].Apply(name)
+	if identifier(property) {
+		return property
+	}
+	return strconv.Quote(property)
+}
+
+// member returns the accessor of the property of a field, e.g ".name" or
+// `["user-id"]`.
+func (r *renderer) member(name string) string {
+	var property = r.property(name)
+	if identifier(property) {
+		return "." + property
+	}
+	return "[" + property + "]"
+}
+
+// parameter returns true if the property of a field can be declared as a
+// constructor parameter, which requires an identifier that is not a
+// keyword.
+func (r *renderer) parameter(name string) bool {
+	var property = r.property(name)
+	return identifier(property) && !r.names.Keywords[property]
+}
+
+// identifier returns true if the name is a valid identifier.
+func identifier(name string) bool {
+	for index, char := range name {
+		if char == '_' || char == '$' || unicode.IsLetter(char) || (index != 0 && unicode.IsDigit(char)) {
+			continue
+		}
+		return false
+	}
+	return name != ""
+}
+
+// signature returns the name, arguments and return type of a method.
+func (r *renderer) signature(def rewrite.MethodDefinition) string {
+	var args = make([]string, 0, len(def.Arguments))
+	for _, arg := range def.Arguments {
+		args = append(args, fmt.Sprintf("%s: %s", r.names.Name(naming.Variable, arg.Name), r.typeName(arg.Type)))
+	}
+	return fmt.Sprintf("%s(%s): %s", r.names.Name(naming.Method, def.Name), strings.Join(args, ", "), r.returnType(def.Returns))
+}
+
+// returnType returns the TypeScript return type for a method, where
+// multiple return values are expressed as a tuple.
+func (r *renderer) returnType(returns []rewrite.ReturnDefinition) string {
+	switch len(returns) {
+	case 0:
+		return "void"
+	case 1:
+		return r.typeName(returns[0].Type)
+	}
+
+	var types = make([]string, 0, len(returns))
+	for _, ret := range returns {
+		types = append(types, r.typeName(ret.Type))
+	}
+	return "[" + strings.Join(types, ", ") + "]"
+}
+
+// stub renders the body of a method, as method bodies are not
+// translated, methods with return values throw when called.
+func (r *renderer) stub(indent string, def rewrite.MethodDefinition) {
+	if len(def.Returns) != 0 {
+		r.Printf("%sthrow new Error(\"not implemented\");\n", indent)
+	}
+}
+
+// typeName returns the TypeScript type for the giving type definition.
+func (r *renderer) typeName(definition rewrite.Applicable) string {
+	if definition == nil {
+		return "any"
+	}
+
+	switch def := definition.Elem().(type) {
+	case rewrite.TypeDefinition:
+		return r.baseType(def)
+	case rewrite.DataTypeDefinition:
+		if def.Name == "" {
+			return r.typeName(def.Type)
+		}
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.DataDefinition:
+		return r.names.Name(naming.Type, def.Name)
+	case rewrite.FutureDefinition:
+		return fmt.Sprintf("Promise<%s>", r.typeName(def.Type))
+	case rewrite.StreamDefinition:
+		return fmt.Sprintf("AsyncIterable<%s>", r.typeName(def.Type))
+	case rewrite.ChannelDefinition:
+		return fmt.Sprintf("AsyncIterable<%s>", r.typeName(def.Type))
+	case rewrite.MethodDefinition:
+		var args = make([]string, 0, len(def.Arguments))
+		for _, arg := range def.Arguments {
+			args = append(args, fmt.Sprintf("%s: %s", r.names.Name(naming.Variable, arg.Name), r.typeName(arg.Type)))
+		}
+		return fmt.Sprintf("(%s) => %s", strings.Join(args, ", "), r.returnType(def.Returns))
+	case rewrite.FieldDefinition:
+		return r.typeName(def.Type)
+	case rewrite.ReturnDefinition:
+		return r.typeName(def.Type)
+	}
+
+	r.SetErr(fmt.Errorf("typescript: %T is not a type definition", definition.Elem()))
+	return "any"
+}
+
+func (r *renderer) baseType(def rewrite.TypeDefinition) string {
+	var typ, ok = r.types.Lookup(def)
+	if !ok {
+		return "any"
+	}
+	if typ.Import != "" {
+		if r.imports[typ.Import] == nil {
+			r.imports[typ.Import] = map[string]bool{}
+		}
+		r.imports[typ.Import][typ.Name] = true
+	}
+	return typ.Name
+}
@@ -0,0 +1,157 @@
+package typescript_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/generators/typescript"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	var user = &rewrite.DataDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "User", Description: "User is a registered user."},
+		Fields: []rewrite.FieldDefinition{
+			{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "age"}, Type: &rewrite.TypeDefinition{Type: rewrite.Integer}},
+			{BaseDefinition: rewrite.BaseDefinition{Name: "joined"}, Type: &rewrite.TypeDefinition{Type: rewrite.Time}},
+		},
+		Methods: []rewrite.MethodDefinition{
+			{
+				BaseDefinition: rewrite.BaseDefinition{Name: "friends"},
+				Returns: []rewrite.ReturnDefinition{
+					{Type: &rewrite.StreamDefinition{Type: &rewrite.DataTypeDefinition{BaseDefinition: rewrite.BaseDefinition{Name: "User"}}}},
+				},
+			},
+		},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "models", Description: "Package models.", Version: "0.1"},
+		Definitions: []rewrite.Applicable{
+			user,
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "fetchUser"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "id"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+				Returns: []rewrite.ReturnDefinition{
+					{Type: &rewrite.FutureDefinition{Type: user}},
+				},
+			},
+		},
+	}
+
+	var code, err = typescript.Render(pkg, typescript.Options{})
+	require.NoError(t, err)
+	require.Contains(t, string(code), "// Package models.\n// Version: 0.1\n")
+	require.Contains(t, string(code), "/** User is a registered user. */\nexport interface User {\n\tname: string;\n\tage: number;\n\tjoined: Date;\n\tfriends(): AsyncIterable<User>;\n}\n")
+	require.Contains(t, string(code), "export function fetchUser(id: string): Promise<User> {\n\tthrow new Error(\"not implemented\");\n}\n")
+
+	code, err = typescript.Render(pkg, typescript.Options{Classes: true})
+	require.NoError(t, err)
+	require.Contains(t, string(code), "export class User {\n\tconstructor(\n\t\tpublic name: string,\n\t\tpublic age: number,\n\t\tpublic joined: Date,\n\t) {}\n\n\tfriends(): AsyncIterable<User> {\n")
+}
+
+func TestRenderInvalidType(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataTypeDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "Broken"},
+				Type:           &rewrite.IfDefinition{},
+			},
+		},
+	}
+
+	var _, err = typescript.Render(pkg, typescript.Options{})
+	require.Error(t, err)
+}
+
+func TestRenderRenamed(t *testing.T) {
+	var email = &rewrite.DataTypeDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "email_address"},
+		Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+	}
+
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "accounts"},
+		Definitions: []rewrite.Applicable{
+			email,
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "account"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "email_address"}, Type: email},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "active"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+			},
+		},
+	}
+
+	var out, err = typescript.Render(pkg, typescript.Options{})
+	require.NoError(t, err)
+	var code = string(out)
+	require.Contains(t, code, "export type EmailAddress = string;\n")
+	require.Contains(t, code, "\temail_address: EmailAddress;\n")
+}
+
+func TestRenderKeywords(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		BaseDefinition: rewrite.BaseDefinition{Name: "search"},
+		Definitions: []rewrite.Applicable{
+			&rewrite.MethodDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "find"},
+				Arguments: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "for"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+				Returns: []rewrite.ReturnDefinition{{Type: &rewrite.TypeDefinition{Type: rewrite.String}}},
+			},
+			&rewrite.VariableDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "if"},
+				Type:           &rewrite.TypeDefinition{Type: rewrite.String},
+				Assign:         &rewrite.AssignmentDefinition{Value: &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "\"any\""}}},
+			},
+		},
+	}
+
+	var out, err = typescript.Render(pkg, typescript.Options{})
+	require.NoError(t, err)
+	var code = string(out)
+	require.Contains(t, code, "export function find(for_: string): string {")
+	require.Contains(t, code, "export let if_: string = \"any\";")
+}
+
+func TestRenderProperties(t *testing.T) {
+	var pkg = rewrite.PackageDefinition{
+		Definitions: []rewrite.Applicable{
+			&rewrite.DataDefinition{
+				BaseDefinition: rewrite.BaseDefinition{Name: "User"},
+				Fields: []rewrite.FieldDefinition{
+					{BaseDefinition: rewrite.BaseDefinition{Name: "name"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "default"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+					{BaseDefinition: rewrite.BaseDefinition{Name: "user-id"}, Type: &rewrite.TypeDefinition{Type: rewrite.String}},
+				},
+			},
+		},
+	}
+
+	var code, err = typescript.Render(pkg, typescript.Options{})
+	require.NoError(t, err)
+	require.Contains(t, string(code), "export interface User {\n\tname: string;\n\tdefault: string;\n\t\"user-id\": string;\n}\n")
+
+	code, err = typescript.Render(pkg, typescript.Options{Classes: true})
+	require.NoError(t, err)
+	require.Contains(t, string(code), `export class User {
+	default: string;
+	"user-id": string;
+
+	constructor(
+		public name: string,
+		default_: string,
+		userId: string,
+	) {
+		this.default = default_;
+		this["user-id"] = userId;
+	}
+}
+`)
+}
@@ -6,4 +6,5 @@ require (
 	github.com/dave/jennifer v1.4.0
 	github.com/influx6/npkg v0.0.0-20191025152151-9a1f4eb01bce
 	github.com/stretchr/testify v1.4.0
+	gopkg.in/yaml.v2 v2.2.2
 )
@@ -5,12 +5,13 @@ import (
 	"testing"
 
 	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/stackexpr"
 	"github.com/stretchr/testify/require"
 )
 
 func TestStackDefinitions(t *testing.T) {
 	var jsondef JSONDefinition
-	var res, err = rewrite.Define(func(obj *rewrite.Description) {
+	var res, err = stackexpr.Describe(func(obj rewrite.Stack) {
 		Object(obj, func() {
 			Name(obj, "Nature")
 			Desc(obj, "Desc")
@@ -50,7 +51,7 @@ func (j *Rob) Apply(v interface{}) error {
 	return nil
 }
 
-func Object(r *rewrite.Description, fn func()) {
+func Object(r rewrite.Stack, fn func()) {
 	var obj Rob
 
 	// Push object into task so functions
@@ -61,10 +62,7 @@ func Object(r *rewrite.Description, fn func()) {
 	// context
 	r.Pop()
 
-	var parent, err = r.Get()
-	if err != nil {
-		return
-	}
+	var parent = r.Current()
 
 	objParent, ok := parent.(*JSONDefinition)
 	if !ok {
@@ -75,11 +73,8 @@ func Object(r *rewrite.Description, fn func()) {
 	objParent.Target = &obj
 }
 
-func Name(r *rewrite.Description, name string) {
-	var parent, err = r.Get()
-	if err != nil {
-		return
-	}
+func Name(r rewrite.Stack, name string) {
+	var parent = r.Current()
 
 	objParent, ok := parent.(*Rob)
 	if !ok {
@@ -90,11 +85,8 @@ func Name(r *rewrite.Description, name string) {
 	objParent.Name = name
 }
 
-func Desc(r *rewrite.Description, desc string) {
-	var parent, err = r.Get()
-	if err != nil {
-		return
-	}
+func Desc(r rewrite.Stack, desc string) {
+	var parent = r.Current()
 
 	objParent, ok := parent.(*Rob)
 	if !ok {
@@ -56,129 +56,144 @@ func UseMemory(target *Description, mem rewrite.MemoryLayout)   {
 	target.SetErr(rewrite.ErrNotApplicable)
 }
 
+// describe pushes def for fn to describe, then pops and applies it to
+// the definition it is described within.
+func describe(target *Description, def rewrite.Applicable, fn func()) {
+	target.Push(def)
+	fn()
+	target.Release()
+}
+
+func UseType(target *Description, fn func()) rewrite.TypeDefinition {
+	var obj rewrite.TypeDefinition
+	describe(target, &obj, fn)
+	return obj
+}
+
+func UseFuture(target *Description, fn func()) rewrite.FutureDefinition {
+	var obj rewrite.FutureDefinition
+	describe(target, &obj, fn)
+	return obj
+}
+
+func UseStream(target *Description, fn func()) rewrite.StreamDefinition {
+	var obj rewrite.StreamDefinition
+	describe(target, &obj, fn)
+	return obj
+}
+
 func UseAnnotation(target *Description, text string, fn func()) rewrite.AnnotationDefinition {
 	var field rewrite.AnnotationDefinition
 	field.Content = text
-	target.Push(&field)
-	fn()
+	describe(target, &field, fn)
 	return field
 }
 
 func UseComment(target *Description, fn func()) rewrite.CommentDefinition {
 	var field rewrite.CommentDefinition
-	target.Push(&field)
-	fn()
+	describe(target, &field, fn)
 	return field
 }
 
 func UseCommentText(target *Description, text string) {
-	if comment, ok := target.Get().Elem().(*rewrite.CommentDefinition); ok {
+	if comment, ok := target.Get().(*rewrite.CommentDefinition); ok {
 		comment.Contents = append(comment.Contents, text)
+		return
 	}
+	target.SetErr(rewrite.ErrNotApplicable)
 }
 
-func UseValue(target *Description, value interface{})  {
-	//if obj, ok := target.Get().Elem().(*astlang.VariableDefinition); ok {
-	//	//obj.Value = value
-	//}
+// UseValue assigns value to the variable or constant being described.
+func UseValue(target *Description, value rewrite.Applicable) {
+	if variable, ok := target.Get().(*rewrite.VariableDefinition); ok {
+		variable.Assign = &rewrite.AssignmentDefinition{Value: value}
+		return
+	}
+	target.SetErr(rewrite.ErrNotApplicable)
 }
 
 func UseConstant(target *Description, fn func()) rewrite.VariableDefinition {
 	var field rewrite.VariableDefinition
 	field.Constant = true
-	target.Push(&field)
-	fn()
+	describe(target, &field, fn)
 	return field
 }
 
 func UseVariable(target *Description, fn func()) rewrite.VariableDefinition {
 	var field rewrite.VariableDefinition
-	target.Push(&field)
-	fn()
+	describe(target, &field, fn)
 	return field
 }
 
 func UseReturn(target *Description, fn func()) rewrite.ReturnDefinition {
 	var field rewrite.ReturnDefinition
-	target.Push(&field)
-	fn()
+	describe(target, &field, fn)
 	return field
 }
 
 func UseResult(target *Description, fn func()) rewrite.ResultDefinition {
 	var field rewrite.ResultDefinition
-	target.Push(&field)
-	fn()
+	describe(target, &field, fn)
 	return field
 }
 
 func UseField(target *Description, fn func()) rewrite.FieldDefinition {
 	var field rewrite.FieldDefinition
-	target.Push(&field)
-	fn()
+	describe(target, &field, fn)
 	return field
 }
 
 func UseDataType(target *Description, fn func()) rewrite.DataTypeDefinition {
 	var dt rewrite.DataTypeDefinition
-	target.Push(&dt)
-	fn()
+	describe(target, &dt, fn)
 	return dt
 }
 
 func UseData(target *Description, fn func()) rewrite.DataDefinition {
 	var dt rewrite.DataDefinition
-	target.Push(&dt)
-	fn()
+	describe(target, &dt, fn)
 	return dt
 }
 
 func UseMethod(target *Description, fn func()) rewrite.MethodDefinition {
 	var obj rewrite.MethodDefinition
-	target.Push(&obj)
-	fn()
+	describe(target, &obj, fn)
 	return obj
 }
 
 func UseMethodCall(target *Description, fn func()) rewrite.MethodCallDefinition {
 	var obj rewrite.MethodCallDefinition
-	target.Push(&obj)
-	fn()
+	describe(target, &obj, fn)
 	return obj
 }
 
 func UseFor(target *Description, fn func()) rewrite.ForDefinition {
 	var obj rewrite.ForDefinition
-	target.Push(&obj)
-	fn()
+	describe(target, &obj, fn)
 	return obj
 }
 
 func UseLoop(target *Description, fn func()) rewrite.LoopDefinition {
 	var obj rewrite.LoopDefinition
-	target.Push(&obj)
-	fn()
+	describe(target, &obj, fn)
 	return obj
 }
 
 func UseIf(target *Description, fn func()) rewrite.IfDefinition {
 	var obj rewrite.IfDefinition
-	target.Push(&obj)
-	fn()
+	describe(target, &obj, fn)
 	return obj
 }
 
 func UseSwitch(target *Description, fn func()) rewrite.SwitchDefinition {
 	var obj rewrite.SwitchDefinition
-	target.Push(&obj)
-	fn()
+	describe(target, &obj, fn)
 	return obj
 }
 
 func UseCase(target *Description, fn func()) rewrite.CaseDefinition {
 	var obj rewrite.CaseDefinition
-	target.Push(&obj)
-	fn()
+	describe(target, &obj, fn)
 	return obj
 }
 
@@ -187,22 +202,41 @@ type CanBody interface {
 }
 
 func UseBody(target *Description, body rewrite.Applicable)  {
-	if canBody, ok := target.Get().Elem().(CanBody); ok {
+	if canBody, ok := target.Get().(CanBody); ok {
 		canBody.SetBody(body)
+		return
 	}
+	target.SetErr(rewrite.ErrNotApplicable)
 }
 
-func UseCondition(target *Description, fn func()) rewrite.ConditionDefinition {
-	var obj rewrite.ConditionDefinition
+// UseBlock describes a BlockDefinition of statements, which becomes the
+// body of a parent with one or is applied to the parent otherwise, e.g
+// as the body of a method.
+func UseBlock(target *Description, fn func()) rewrite.BlockDefinition {
+	var obj rewrite.BlockDefinition
 	target.Push(&obj)
 	fn()
+	target.Pop()
+
+	if canBody, ok := target.Get().(CanBody); ok {
+		canBody.SetBody(&obj)
+		return obj
+	}
+	if err := target.Get().Apply(&obj); err != nil {
+		target.SetErr(err)
+	}
+	return obj
+}
+
+func UseCondition(target *Description, fn func()) rewrite.ConditionDefinition {
+	var obj rewrite.ConditionDefinition
+	describe(target, &obj, fn)
 	return obj
 }
 
 func UseOperator(target *Description, operator rewrite.Operator, fn func()) rewrite.OperatorDefinition {
 	var obj rewrite.OperatorDefinition
 	obj.Operator = operator
-	target.Push(&obj)
-	fn()
+	describe(target, &obj, fn)
 	return obj
 }
@@ -0,0 +1,204 @@
+package stackexpr_test
+
+import (
+	"testing"
+
+	"github.com/influx6/rewrite"
+	"github.com/influx6/rewrite/stackexpr"
+	"github.com/stretchr/testify/require"
+)
+
+// describe runs fn over a package definition with a stackexpr Description.
+func describe(fn func(target *stackexpr.Description)) (rewrite.PackageDefinition, error) {
+	var pkg rewrite.PackageDefinition
+	var _, err = stackexpr.Describe(func(stack rewrite.Stack) {
+		fn(stack.(*stackexpr.Description))
+	})(&pkg)
+	return pkg, err
+}
+
+func TestUseAttachesToParent(t *testing.T) {
+	var pkg, err = describe(func(target *stackexpr.Description) {
+		stackexpr.UseName(target, "billing")
+		stackexpr.UseData(target, func() {
+			stackexpr.UseName(target, "Invoice")
+			stackexpr.UseAnnotation(target, "json", func() {
+				stackexpr.UseName(target, "serialize")
+			})
+			stackexpr.UseField(target, func() {
+				stackexpr.UseName(target, "amount")
+				stackexpr.UseType(target, func() {
+					stackexpr.UseBaseType(target, rewrite.Integer)
+					stackexpr.UseMemory(target, rewrite.Bit32)
+				})
+			})
+			stackexpr.UseMethod(target, func() {
+				stackexpr.UseName(target, "paid")
+				stackexpr.UseReturn(target, func() {
+					stackexpr.UseFuture(target, func() {
+						stackexpr.UseType(target, func() {
+							stackexpr.UseBaseType(target, rewrite.String)
+						})
+					})
+				})
+			})
+		})
+		stackexpr.UseComment(target, func() {
+			stackexpr.UseCommentText(target, "generated")
+		})
+	})
+	require.NoError(t, err)
+	require.Equal(t, "billing", pkg.Name)
+	require.Len(t, pkg.Definitions, 2)
+
+	var invoice = pkg.Definitions[0].Elem().(rewrite.DataDefinition)
+	require.Equal(t, "Invoice", invoice.Name)
+	require.True(t, invoice.Annotations.Has("serialize"))
+	require.Len(t, invoice.Fields, 1)
+	require.Equal(t, "amount", invoice.Fields[0].Name)
+	require.Equal(t, rewrite.TypeDefinition{Type: rewrite.Integer, Memory: rewrite.Bit32}, invoice.Fields[0].Type.Elem())
+
+	require.Len(t, invoice.Methods, 1)
+	require.Len(t, invoice.Methods[0].Returns, 1)
+	var future = invoice.Methods[0].Returns[0].Type.Elem().(rewrite.FutureDefinition)
+	require.Equal(t, rewrite.TypeDefinition{Type: rewrite.String}, future.Type.Elem())
+
+	var comment = pkg.Definitions[1].Elem().(rewrite.CommentDefinition)
+	require.Equal(t, []string{"generated"}, comment.Contents)
+}
+
+func TestUseReturnsDescribed(t *testing.T) {
+	var stream rewrite.StreamDefinition
+	var _, err = describe(func(target *stackexpr.Description) {
+		stream = stackexpr.UseStream(target, func() {
+			stackexpr.UseType(target, func() {
+				stackexpr.UseBaseType(target, rewrite.Decimal)
+			})
+		})
+	})
+	require.NoError(t, err)
+	require.Equal(t, rewrite.TypeDefinition{Type: rewrite.Decimal}, stream.Type.Elem())
+}
+
+func TestUseSwitch(t *testing.T) {
+	var sw rewrite.SwitchDefinition
+	var _, err = describe(func(target *stackexpr.Description) {
+		stackexpr.UseMethod(target, func() {
+			sw = stackexpr.UseSwitch(target, func() {
+				stackexpr.UseCondition(target, func() {
+					stackexpr.UseName(target, "kind")
+				})
+				stackexpr.UseCase(target, func() {})
+				stackexpr.UseCase(target, func() {})
+			})
+		})
+	})
+	require.NoError(t, err)
+	require.Equal(t, "kind", sw.Condition.Name)
+	require.Len(t, sw.Cases, 2)
+}
+
+func TestUseNotApplicable(t *testing.T) {
+	var _, err = describe(func(target *stackexpr.Description) {
+		stackexpr.UseData(target, func() {
+			stackexpr.UseBaseType(target, rewrite.String)
+		})
+	})
+	require.Equal(t, rewrite.ErrNotApplicable, err)
+
+	_, err = describe(func(target *stackexpr.Description) {
+		stackexpr.UseData(target, func() {
+			stackexpr.UseComment(target, func() {})
+		})
+	})
+	require.Equal(t, rewrite.ErrNotApplicable, err)
+}
+
+func TestUseNestedConditions(t *testing.T) {
+	var loop rewrite.ForDefinition
+	var sw rewrite.SwitchDefinition
+	var _, err = describe(func(target *stackexpr.Description) {
+		stackexpr.UseMethod(target, func() {
+			stackexpr.UseBlock(target, func() {
+				loop = stackexpr.UseFor(target, func() {
+					stackexpr.UseVariable(target, func() {
+						stackexpr.UseName(target, "i")
+						stackexpr.UseValue(target, &rewrite.Value{BaseDefinition: rewrite.BaseDefinition{Name: "0"}})
+					})
+					stackexpr.UseCondition(target, func() {
+						stackexpr.UseVariable(target, func() {
+							stackexpr.UseName(target, "i")
+						})
+						stackexpr.UseOperator(target, rewrite.LessThan, func() {})
+						stackexpr.UseVariable(target, func() {
+							stackexpr.UseName(target, "limit")
+						})
+					})
+					stackexpr.UseCondition(target, func() {
+						stackexpr.UseVariable(target, func() {
+							stackexpr.UseName(target, "i")
+						})
+						stackexpr.UseOperator(target, rewrite.Increment, func() {})
+					})
+					stackexpr.UseBlock(target, func() {
+						stackexpr.UseMethodCall(target, func() {
+							stackexpr.UseName(target, "notify")
+						})
+					})
+				})
+				sw = stackexpr.UseSwitch(target, func() {
+					stackexpr.UseCase(target, func() {
+						stackexpr.UseCondition(target, func() {
+							stackexpr.UseName(target, "paid")
+						})
+						stackexpr.UseBody(target, &rewrite.ReturnDefinition{})
+					})
+				})
+			})
+		})
+	})
+	require.NoError(t, err)
+
+	var start = loop.Left.Elem().(rewrite.VariableDefinition)
+	require.Equal(t, "i", start.Name)
+	require.Equal(t, "0", start.Assign.Value.(*rewrite.Value).Name)
+
+	var condition = loop.Middle.Elem().(rewrite.ConditionDefinition)
+	require.Equal(t, rewrite.LessThan, condition.Operator.Operator)
+	require.Equal(t, "i", condition.Left.(*rewrite.VariableDefinition).Name)
+	require.Equal(t, "limit", condition.Right.(*rewrite.VariableDefinition).Name)
+
+	var post = loop.End.Elem().(rewrite.ConditionDefinition)
+	require.Equal(t, rewrite.Increment, post.Operator.Operator)
+	require.Nil(t, post.Right)
+
+	var body = loop.Body.Elem().(rewrite.BlockDefinition)
+	require.Len(t, body.Statements, 1)
+
+	require.Len(t, sw.Cases, 1)
+	require.Equal(t, "paid", sw.Cases[0].Condition.Name)
+	require.NotNil(t, sw.Cases[0].Body)
+}
+
+func TestUseWrongParent(t *testing.T) {
+	var _, err = describe(func(target *stackexpr.Description) {
+		stackexpr.UseData(target, func() {
+			stackexpr.UseBody(target, &rewrite.ReturnDefinition{})
+		})
+	})
+	require.Equal(t, rewrite.ErrNotApplicable, err)
+
+	_, err = describe(func(target *stackexpr.Description) {
+		stackexpr.UseData(target, func() {
+			stackexpr.UseCommentText(target, "orphan")
+		})
+	})
+	require.Equal(t, rewrite.ErrNotApplicable, err)
+
+	_, err = describe(func(target *stackexpr.Description) {
+		stackexpr.UseData(target, func() {
+			stackexpr.UseValue(target, &rewrite.Value{})
+		})
+	})
+	require.Equal(t, rewrite.ErrNotApplicable, err)
+}
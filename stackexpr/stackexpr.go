@@ -63,7 +63,7 @@ func (s *Description) IsUsable() bool {
 // If there are no elements in stack, a default EmptyApplicable
 // is returned.
 func (s *Description) Current() Applicable {
-	var target = s.get()
+	var target = s.Get()
 	if target == nil {
 		return defaultEmptyApplicable
 	}
@@ -93,7 +93,7 @@ func (s *Description) Release() {
 	}
 
 	var current = s.Pop()
-	var parent = s.get()
+	var parent = s.Get()
 	if err := parent.Apply(current); err != nil {
 		s.SetErr(err)
 	}
@@ -109,7 +109,7 @@ func ApplyTo(stack Stack, definitions ...Definition) {
 }
 
 // Get returns current Applicable object in stack.
-func (s *Description) get() Applicable {
+func (s *Description) Get() Applicable {
 	if len(s.stacks) == 0 {
 		return nil
 	}